@@ -0,0 +1,72 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	// accentLevels is the number of MMCQ levels used to find accent candidates.
+	accentLevels = 4
+
+	// accentSaturation is the smallest HSV saturation of an accent color.
+	accentSaturation = 0.3
+
+	// accentMinValue and accentMaxValue bound the HSV value of an accent
+	// color, so that it is neither muddy nor washed out.
+	accentMinValue = 0.35
+	accentMaxValue = 0.95
+)
+
+// Accent takes in an image, such as a wallpaper, and returns a single accent
+// color suitable for tinting a user interface, in the manner of wallpaper
+// driven accents on Android & Windows. Rather than the dominant color, the
+// accent is the candidate that best balances saturation against how much of
+// the image it covers, among those that are saturated, yet neither too dark
+// nor too bright. Returns false if no color in the image qualifies, such as
+// for a grayscale image.
+func Accent(img image.Image) (color.RGBA, bool) {
+
+	var quantizer Quantizer
+	quantizer.extract(img, img.Bounds())
+
+	partitions := quantizer.partition(quantizer.pixels, nil, accentLevels, 1<<accentLevels)
+	defer quantizer.release()
+
+	var total float64
+	for _, partition := range partitions {
+		total += partition.population
+	}
+
+	var best color.RGBA
+	var bestScore float64
+	found := false
+
+	for _, partition := range partitions {
+		if partition.population == 0 {
+			continue
+		}
+
+		clr := partition.average(false)
+		hsv := ToHSV(clr)
+
+		if hsv.S < accentSaturation || hsv.V < accentMinValue || hsv.V > accentMaxValue {
+			continue
+		}
+
+		// Coverage counts for less than saturation, so that a small vivid
+		// region can beat a large dull one
+		score := hsv.S * math.Sqrt(partition.population/total)
+
+		if !found || score > bestScore {
+			best, bestScore, found = clr, score, true
+		}
+	}
+
+	return best, found
+}
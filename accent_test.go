@@ -0,0 +1,83 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccent(t *testing.T) {
+
+	var (
+		gray   = color.RGBA{128, 128, 128, 0xFF}
+		orange = color.RGBA{230, 120, 20, 0xFF}
+		dull   = color.RGBA{150, 130, 120, 0xFF}
+		navy   = color.RGBA{10, 10, 60, 0xFF}
+		pastel = color.RGBA{250, 245, 240, 0xFF}
+	)
+
+	tests := []struct {
+		title  string
+		pixel  func(x int, y int) color.RGBA
+		accent color.RGBA
+		found  bool
+	}{
+		{
+			title: "grayscale",
+			pixel: func(x int, _ int) color.RGBA {
+				return color.RGBA{uint8(x * 16), uint8(x * 16), uint8(x * 16), 0xFF}
+			},
+		},
+		{
+			title: "small vivid region over dull background",
+			pixel: func(x int, y int) color.RGBA {
+				if x < 4 && y < 4 {
+					return orange
+				}
+				return dull
+			},
+			accent: orange,
+			found:  true,
+		},
+		{
+			title: "too dark and too bright",
+			pixel: func(x int, _ int) color.RGBA {
+				if x < 8 {
+					return navy
+				}
+				return pastel
+			},
+		},
+		{
+			title: "gray and orange",
+			pixel: func(x int, _ int) color.RGBA {
+				if x < 15 {
+					return gray
+				}
+				return orange
+			},
+			accent: orange,
+			found:  true,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			accent, found := Accent(testImage(16, 16, test.pixel))
+
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.accent, accent)
+
+		})
+	}
+
+}
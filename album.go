@@ -0,0 +1,150 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+const (
+	// albumLevels is the number of MMCQ levels used to find album colors.
+	albumLevels = 3
+
+	// albumDark and albumLight bound the luminance of the colors considered,
+	// so that black & white bars and borders are ignored.
+	albumDark  = 0.02
+	albumLight = 0.9
+
+	// albumBar is the smallest fraction of the pixels along a row or column
+	// that must be near-black, or that must be near-white, for it to be
+	// cropped as a bar.
+	albumBar = 0.98
+
+	// albumDistinct is the smallest DeltaE between the primary & secondary
+	// colors.
+	albumDistinct = 20
+
+	// albumText is the smallest contrast ratio between the primary color and
+	// the text color, the WCAG AA ratio for body text.
+	albumText = 4.5
+)
+
+// AlbumColors holds the colors that a music player needs to theme its user
+// interface around a piece of album art.
+type AlbumColors struct {
+	// Primary is the dominant color, suitable for the background.
+	Primary color.RGBA
+
+	// Secondary is the next most dominant color that is distinct from the
+	// primary, suitable for accents.
+	Secondary color.RGBA
+
+	// Text is readable against the primary color, with a contrast ratio of at
+	// least 4.5, and is tinted with the secondary color where possible.
+	Text color.RGBA
+}
+
+// Album takes in a piece of album art, and returns the colors needed to theme
+// a user interface around it. Letterboxing & pillarboxing are cropped, and
+// near-black & near-white pixels are ignored, unless nothing else remains.
+// When no color is distinct enough from the primary, the secondary is the
+// primary shifted towards the text color.
+func Album(img image.Image) AlbumColors {
+
+	rect := letterbox(img)
+
+	quantizer := NewQuantizer(WithPixelFilter(func(x int, y int, c color.RGBA) bool {
+		return image.Pt(x, y).In(rect) && !bar(c)
+	}))
+
+	result, _ := quantizer.Analyze(img, albumLevels)
+	if len(result.Colors) == 0 {
+		result = Analyze(img, albumLevels)
+	}
+	if len(result.Colors) == 0 {
+		return AlbumColors{
+			Primary:   color.RGBA{0, 0, 0, 0xFF},
+			Secondary: color.RGBA{0, 0, 0, 0xFF},
+			Text:      color.RGBA{255, 255, 255, 0xFF},
+		}
+	}
+
+	var album AlbumColors
+	album.Primary = result.Colors[0].RGBA
+
+	secondary, found := album.Primary, false
+	for _, swatch := range result.Colors[1:] {
+		if DeltaE(swatch.RGBA, album.Primary) >= albumDistinct {
+			secondary, found = swatch.RGBA, true
+			break
+		}
+	}
+
+	// Text is pushed towards whichever of black or white contrasts best with
+	// the primary, which always reaches the target ratio
+	target := color.RGBA{255, 255, 255, 0xFF}
+	if Contrast(album.Primary, color.RGBA{0, 0, 0, 0xFF}) > Contrast(album.Primary, target) {
+		target = color.RGBA{0, 0, 0, 0xFF}
+	}
+
+	album.Text = secondary
+	for step := 1; step <= 20 && Contrast(album.Text, album.Primary) < albumText; step++ {
+		album.Text = Mix(secondary, target, float64(step)/20)
+	}
+
+	album.Secondary = secondary
+	if !found {
+		album.Secondary = Mix(album.Primary, album.Text, 0.3)
+	}
+
+	return album
+}
+
+// letterbox returns the bounds of the given image with any near-black or
+// near-white bars along its edges cropped, never cropping more than half of
+// the image from any side.
+func letterbox(img image.Image) image.Rectangle {
+	rect := img.Bounds()
+
+	// isBar reports whether the given run of pixels is a bar, which is either
+	// dark or light throughout
+	isBar := func(x0 int, y0 int, dx int, dy int, length int) bool {
+		dark, light := 0, 0
+		for i := 0; i < length; i++ {
+			switch luminance := Luminance(rgbaAt(img, x0+i*dx, y0+i*dy)); {
+			case luminance <= albumDark:
+				dark++
+			case luminance >= albumLight:
+				light++
+			}
+		}
+		return float64(maxInt(dark, light)) >= albumBar*float64(length)
+	}
+
+	top, bottom := rect.Min.Y, rect.Max.Y
+	for top < rect.Min.Y+rect.Dy()/2 && isBar(rect.Min.X, top, 1, 0, rect.Dx()) {
+		top++
+	}
+	for bottom > rect.Max.Y-rect.Dy()/2 && isBar(rect.Min.X, bottom-1, 1, 0, rect.Dx()) {
+		bottom--
+	}
+
+	left, right := rect.Min.X, rect.Max.X
+	for left < rect.Min.X+rect.Dx()/2 && isBar(left, top, 0, 1, bottom-top) {
+		left++
+	}
+	for right > rect.Max.X-rect.Dx()/2 && isBar(right-1, top, 0, 1, bottom-top) {
+		right--
+	}
+
+	return image.Rect(left, top, right, bottom)
+}
+
+// bar reports whether the given color is near-black or near-white.
+func bar(clr color.RGBA) bool {
+	luminance := Luminance(clr)
+	return luminance <= albumDark || luminance >= albumLight
+}
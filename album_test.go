@@ -0,0 +1,105 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlbum(t *testing.T) {
+
+	var (
+		black  = color.RGBA{0, 0, 0, 0xFF}
+		navy   = color.RGBA{20, 30, 110, 0xFF}
+		orange = color.RGBA{230, 120, 20, 0xFF}
+		beige  = color.RGBA{200, 180, 150, 0xFF}
+	)
+
+	tests := []struct {
+		title     string
+		pixel     func(x int, y int) color.RGBA
+		rect      image.Rectangle
+		primary   color.RGBA
+		secondary color.RGBA
+	}{
+		{
+			title: "letterboxed",
+			pixel: func(x int, y int) color.RGBA {
+				switch {
+				case y < 4 || y >= 28:
+					return black
+				case x < 8:
+					return orange
+				default:
+					return navy
+				}
+			},
+			rect:      image.Rect(0, 4, 32, 28),
+			primary:   navy,
+			secondary: orange,
+		},
+		{
+			title: "pillarboxed with a white border",
+			pixel: func(x int, y int) color.RGBA {
+				switch {
+				case x < 2 || x >= 30:
+					return white
+				case y < 20:
+					return beige
+				default:
+					return navy
+				}
+			},
+			rect:      image.Rect(2, 0, 30, 32),
+			primary:   beige,
+			secondary: navy,
+		},
+		{
+			title: "black and white only",
+			pixel: func(x int, y int) color.RGBA {
+				if (x/4+y/4)%3 == 0 {
+					return white
+				}
+				return black
+			},
+			rect:      image.Rect(0, 0, 32, 32),
+			primary:   black,
+			secondary: white,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			img := testImage(32, 32, test.pixel)
+			album := Album(img)
+
+			assert.Equal(t, test.rect, letterbox(img))
+			assert.Equal(t, test.primary, album.Primary)
+			assert.Equal(t, test.secondary, album.Secondary)
+			assert.True(t, Contrast(album.Text, album.Primary) >= albumText)
+
+		})
+	}
+}
+
+func TestAlbumIndistinct(t *testing.T) {
+
+	navy := color.RGBA{20, 30, 110, 0xFF}
+	album := Album(testImage(16, 16, func(int, int) color.RGBA { return navy }))
+
+	// With no distinct second color, the secondary is derived from the
+	// primary, and the text is readable against it
+	assert.Equal(t, navy, album.Primary)
+	assert.NotEqual(t, navy, album.Secondary)
+	assert.True(t, Contrast(album.Text, album.Primary) >= albumText)
+}
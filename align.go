@@ -0,0 +1,71 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import "image/color"
+
+// Align takes in a palette and a reference palette, such as the palette of the
+// previous frame of a video, and returns the palette reordered so that each
+// color takes the index of the closest reference color where possible. This
+// avoids needless index churn in delta encoded GIF & APNG streams. Pairs of
+// colors are matched greedily by DeltaE, from the closest pair onwards, and
+// any colors left unmatched fill the remaining indices in their original
+// order.
+func Align(colors []color.RGBA, reference []color.RGBA) []color.RGBA {
+
+	// Only reference indices that exist within the palette can be taken
+	if len(reference) > len(colors) {
+		reference = reference[:len(colors)]
+	}
+
+	// Every distance is computed once, into a single matrix with a row per
+	// color and a column per reference color
+	width := len(reference)
+	distances := make([]float64, len(colors)*width)
+	for i, clr := range colors {
+		for j, ref := range reference {
+			distances[i*width+j] = DeltaE(clr, ref)
+		}
+	}
+
+	aligned := make([]color.RGBA, len(colors))
+	placed := make([]bool, len(colors))
+	taken := make([]bool, len(colors))
+
+	// Match the closest remaining pair each time, preferring the earliest
+	// color and then the earliest reference color on ties
+	for matched := 0; matched < width; matched++ {
+		best, bestI, bestJ := 0.0, -1, -1
+
+		for i := range colors {
+			if placed[i] {
+				continue
+			}
+			for j := 0; j < width; j++ {
+				if distance := distances[i*width+j]; !taken[j] && (bestI < 0 || distance < best) {
+					best, bestI, bestJ = distance, i, j
+				}
+			}
+		}
+
+		aligned[bestJ] = colors[bestI]
+		placed[bestI], taken[bestJ] = true, true
+	}
+
+	// Fill the remaining indices with the remaining colors
+	index := 0
+	for i, clr := range colors {
+		if placed[i] {
+			continue
+		}
+		for taken[index] {
+			index++
+		}
+		aligned[index] = clr
+		taken[index] = true
+	}
+
+	return aligned
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlign(t *testing.T) {
+
+	var (
+		black = color.RGBA{0, 0, 0, 0xFF}
+		white = color.RGBA{255, 255, 255, 0xFF}
+		red   = color.RGBA{255, 0, 0, 0xFF}
+		dark  = color.RGBA{200, 0, 0, 0xFF}
+		blue  = color.RGBA{0, 0, 255, 0xFF}
+	)
+
+	tests := []struct {
+		title     string
+		colors    []color.RGBA
+		reference []color.RGBA
+		aligned   []color.RGBA
+	}{
+		{
+			title:     "empty palette",
+			colors:    []color.RGBA{},
+			reference: []color.RGBA{red},
+			aligned:   []color.RGBA{},
+		},
+		{
+			title:     "empty reference",
+			colors:    []color.RGBA{red, blue},
+			reference: []color.RGBA{},
+			aligned:   []color.RGBA{red, blue},
+		},
+		{
+			title:     "same colors reordered",
+			colors:    []color.RGBA{white, red, black},
+			reference: []color.RGBA{black, white, red},
+			aligned:   []color.RGBA{black, white, red},
+		},
+		{
+			title:     "shifted color keeps its index",
+			colors:    []color.RGBA{dark, blue, white},
+			reference: []color.RGBA{white, red, blue},
+			aligned:   []color.RGBA{white, dark, blue},
+		},
+		{
+			title:     "shorter reference",
+			colors:    []color.RGBA{red, black, blue},
+			reference: []color.RGBA{blue},
+			aligned:   []color.RGBA{blue, red, black},
+		},
+		{
+			title:     "longer reference",
+			colors:    []color.RGBA{black, red},
+			reference: []color.RGBA{white, blue, red},
+			aligned:   []color.RGBA{black, red},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.aligned, Align(test.colors, test.reference))
+
+		})
+	}
+
+}
+
+func TestQuantizerReferencePalette(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), 0, 0xFF}
+	})
+
+	palette := Image(img, 3)
+
+	// Reversing the reference palette should reverse the new palette
+	reversed := make([]color.RGBA, len(palette))
+	for index, clr := range palette {
+		reversed[len(palette)-1-index] = clr
+	}
+
+	aligned, err := NewQuantizer(WithReferencePalette(reversed)).Image(img, 3)
+	require.Nil(t, err)
+
+	assert.Equal(t, reversed, aligned)
+
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// ImageNRGBA performs MMCQ on the given image like Image, but every palette
+// color retains the average alpha of its pixels, rather than being opaque.
+// Levels outside of [0, MaxLevels] are clamped.
+func ImageNRGBA(img image.Image, levels int) []color.NRGBA {
+	var quantizer Quantizer
+	colors, _ := quantizer.ImageNRGBA(img, clampLevels(levels))
+	return colors
+}
+
+// ImageNRGBA performs MMCQ on the given image like Image, but every palette
+// color retains the average alpha of its pixels, rather than being opaque.
+// Pixels are partitioned by their non-premultiplied color, and each palette
+// color is averaged in proportion to the alpha of its pixels, so that nearly
+// transparent pixels barely tint it. The pixel filter is passed each
+// non-premultiplied color with its alpha. WithSimilarityOrder and
+// WithReferencePalette are not applied. Returns ErrInvalidLevels if levels is
+// not within [0, MaxLevels].
+func (q *Quantizer) ImageNRGBA(img image.Image, levels int) ([]color.NRGBA, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return nil, ErrInvalidLevels
+	}
+
+	q.phase(phaseExtract, func() {
+		q.pixels = extractNRGBA(q.pixels, img, img.Bounds())
+		q.retain(img.Bounds())
+	})
+
+	target := 1 << uint(levels)
+	partitions := q.partition(q.pixels, q.weights, levels, target)
+	averages := make([]color.NRGBA, len(partitions), target)
+
+	for index, partition := range partitions {
+		averages[index] = partition.averageNRGBA(q.truncate)
+	}
+
+	// Avoid retaining the caller's pixels once finished
+	q.release()
+
+	if q.short {
+		averages = distinctNRGBA(averages)
+	} else {
+		for index := 0; len(averages) < target; index++ {
+			averages = append(averages, averages[index])
+		}
+	}
+
+	return averages, nil
+}
+
+// averageNRGBA returns the average non-premultiplied color of the box, where
+// its pixels hold non-premultiplied colors. Colors are weighted by alpha, and by
+// the pixel weights if the box is weighted.
+func (b box) averageNRGBA(truncate bool) color.NRGBA {
+	var totalR, totalG, totalB, totalA, total float64
+
+	for index, pixel := range b.pixels {
+		weight := 1.0
+		if b.weights != nil {
+			weight = b.weights[index]
+		}
+
+		alpha := weight * float64(pixel.A)
+		totalR += alpha * float64(pixel.R)
+		totalG += alpha * float64(pixel.G)
+		totalB += alpha * float64(pixel.B)
+		totalA += alpha
+		total += weight
+	}
+
+	// Adding a half rounds the quotient to the nearest value
+	var half float64
+	if !truncate {
+		half = 0.5
+	}
+
+	component := func(sum float64, count float64) uint8 {
+		if count == 0 {
+			return 0
+		}
+		return uint8(math.Min(math.Floor(sum/count+half), 255))
+	}
+
+	return color.NRGBA{
+		component(totalR, totalA),
+		component(totalG, totalA),
+		component(totalB, totalA),
+		component(totalA, total),
+	}
+}
+
+// extractNRGBA appends the pixels of the given image that lie within the given
+// rectangle to the given buffer, after truncating it, as non-premultiplied
+// colors along with their alpha.
+func extractNRGBA(buf []color.RGBA, img image.Image, rect image.Rectangle) []color.RGBA {
+
+	rect = rect.Intersect(img.Bounds())
+	pixels := buf[:0]
+
+	// Images from NRGBA pipelines already hold non-premultiplied colors
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				pix := nrgba.Pix[nrgba.PixOffset(x, y):]
+				pixels = append(pixels, color.RGBA{pix[0], pix[1], pix[2], pix[3]})
+			}
+		}
+		return pixels
+	}
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pixels = append(pixels, color.RGBA{pixel.R, pixel.G, pixel.B, pixel.A})
+		}
+	}
+
+	return pixels
+}
+
+// distinctNRGBA removes repeated colors from the given palette in place,
+// keeping the first occurrence of each.
+func distinctNRGBA(colors []color.NRGBA) []color.NRGBA {
+	seen := make(map[color.NRGBA]bool, len(colors))
+	unique := colors[:0]
+
+	for _, clr := range colors {
+		if !seen[clr] {
+			seen[clr] = true
+			unique = append(unique, clr)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageNRGBA(t *testing.T) {
+
+	// The left half of the image is half transparent red, and the right half
+	// is opaque blue
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x < 2 {
+				img.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 128})
+			} else {
+				img.SetNRGBA(x, y, color.NRGBA{0, 0, 255, 0xFF})
+			}
+		}
+	}
+
+	tests := []struct {
+		title   string
+		img     image.Image
+		levels  int
+		palette []color.NRGBA
+	}{
+		{
+			title:  "averaged alpha",
+			img:    img,
+			levels: 0,
+			palette: []color.NRGBA{
+				{85, 0, 170, 192},
+			},
+		},
+		{
+			title:  "separate alpha",
+			img:    img,
+			levels: 1,
+			palette: []color.NRGBA{
+				{0, 0, 255, 0xFF},
+				{255, 0, 0, 128},
+			},
+		},
+		{
+			title:  "fully transparent",
+			img:    image.NewNRGBA(image.Rect(0, 0, 2, 2)),
+			levels: 1,
+			palette: []color.NRGBA{
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.palette, ImageNRGBA(test.img, test.levels))
+
+		})
+	}
+
+}
+
+func TestImageNRGBAOpaque(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.png"))
+	require.Nil(t, err)
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	// Opaque images have the same palette as without alpha
+	for _, levels := range []int{0, 2, 4} {
+		var opaque []color.NRGBA
+		for _, clr := range Image(img, levels) {
+			opaque = append(opaque, color.NRGBA{clr.R, clr.G, clr.B, clr.A})
+		}
+
+		assert.Equal(t, opaque, ImageNRGBA(img, levels))
+	}
+
+	_, err = NewQuantizer().ImageNRGBA(img, MaxLevels+1)
+	assert.Equal(t, ErrInvalidLevels, err)
+
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package ambilight pushes the colors along the edges of an image to LED
+// strips, using the realtime UDP protocols understood by WLED & Hyperion.
+package ambilight
+
+import (
+	"errors"
+	"image/color"
+	"net"
+
+	"github.com/joshdk/quantize"
+)
+
+// Protocol is a realtime UDP protocol for driving LEDs.
+type Protocol int
+
+const (
+	// DRGB is the WLED protocol that sets every LED from the first onwards,
+	// for up to 490 LEDs.
+	DRGB Protocol = iota
+
+	// DNRGB is the WLED protocol that sets a run of LEDs from a given start
+	// index, allowing any number of LEDs to be split across packets.
+	DNRGB
+
+	// Raw is the protocol of the Hyperion UDP listener, where every packet
+	// holds nothing but the color of every LED.
+	Raw
+)
+
+const (
+	// DefaultPort is the port that WLED listens on for realtime UDP packets.
+	DefaultPort = 21324
+
+	// Timeout is the number of seconds that WLED waits after the last packet
+	// before returning to its normal mode.
+	Timeout = 2
+
+	// maxDRGB & maxDNRGB are the most LEDs that may be sent in a single DRGB
+	// or DNRGB packet.
+	maxDRGB  = 490
+	maxDNRGB = 489
+)
+
+// ErrTooManyLEDs is returned when there are more LEDs than fit in a single
+// packet of a protocol that can not be split across packets.
+var ErrTooManyLEDs = errors.New("too many LEDs for a single packet")
+
+// Colors takes the palettes along the edges of an image, such as those
+// returned by quantize.Edges, and returns the dominant color of every segment
+// in the order that LEDs are commonly wired behind a screen: clockwise from
+// the top left corner, so along the top from left to right, down the right,
+// along the bottom from right to left, and up the left. Segments without a
+// palette are black.
+func Colors(edges quantize.EdgePalettes) []color.RGBA {
+	var colors []color.RGBA
+
+	add := func(palettes [][]color.RGBA, reverse bool) {
+		for index := range palettes {
+			if reverse {
+				index = len(palettes) - 1 - index
+			}
+
+			clr := color.RGBA{0, 0, 0, 0xFF}
+			if len(palettes[index]) > 0 {
+				clr = palettes[index][0]
+			}
+			colors = append(colors, clr)
+		}
+	}
+
+	add(edges.Top, false)
+	add(edges.Right, false)
+	add(edges.Bottom, true)
+	add(edges.Left, true)
+
+	return colors
+}
+
+// Packets encodes the colors of every LED as one or more packets of the given
+// protocol. Returns ErrTooManyLEDs if the protocol can not hold every color.
+func Packets(protocol Protocol, colors []color.RGBA) ([][]byte, error) {
+	switch protocol {
+	case DRGB:
+		if len(colors) > maxDRGB {
+			return nil, ErrTooManyLEDs
+		}
+		return [][]byte{append([]byte{2, Timeout}, rgb(colors)...)}, nil
+
+	case DNRGB:
+		var packets [][]byte
+		for start := 0; start < len(colors); start += maxDNRGB {
+			end := start + maxDNRGB
+			if end > len(colors) {
+				end = len(colors)
+			}
+
+			header := []byte{4, Timeout, byte(start >> 8), byte(start)}
+			packets = append(packets, append(header, rgb(colors[start:end])...))
+		}
+		return packets, nil
+
+	case Raw:
+		return [][]byte{rgb(colors)}, nil
+
+	default:
+		return nil, errors.New("unknown protocol")
+	}
+}
+
+// rgb packs the given colors as consecutive red, green, & blue bytes.
+func rgb(colors []color.RGBA) []byte {
+	buf := make([]byte, 0, len(colors)*3)
+	for _, clr := range colors {
+		buf = append(buf, clr.R, clr.G, clr.B)
+	}
+	return buf
+}
+
+// Sender sends the colors of every LED to a single device.
+type Sender struct {
+	protocol Protocol
+	conn     net.Conn
+}
+
+// Dial returns a sender that sends packets of the given protocol to the
+// device at the given UDP address, such as "192.168.1.50:21324".
+func Dial(address string, protocol Protocol) (*Sender, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sender{protocol: protocol, conn: conn}, nil
+}
+
+// Send sends the colors of every LED to the device.
+func (s *Sender) Send(colors []color.RGBA) error {
+	packets, err := Packets(s.protocol, colors)
+	if err != nil {
+		return err
+	}
+
+	for _, packet := range packets {
+		if _, err := s.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the connection to the device.
+func (s *Sender) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package ambilight
+
+import (
+	"fmt"
+	"image/color"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joshdk/quantize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColors(t *testing.T) {
+
+	shade := func(value uint8) []color.RGBA {
+		return []color.RGBA{{value, value, value, 0xFF}}
+	}
+
+	edges := quantize.EdgePalettes{
+		Top:    [][]color.RGBA{shade(1), shade(2)},
+		Right:  [][]color.RGBA{shade(3), shade(4)},
+		Bottom: [][]color.RGBA{shade(5), shade(6)},
+		Left:   [][]color.RGBA{shade(7), {}},
+	}
+
+	var values []uint8
+	for _, clr := range Colors(edges) {
+		values = append(values, clr.R)
+	}
+
+	assert.Equal(t, []uint8{1, 2, 3, 4, 6, 5, 0, 7}, values)
+}
+
+func TestPackets(t *testing.T) {
+
+	colors := []color.RGBA{{1, 2, 3, 0xFF}, {4, 5, 6, 0xFF}}
+
+	many := make([]color.RGBA, 500)
+	many[489] = color.RGBA{7, 8, 9, 0xFF}
+
+	tests := []struct {
+		title    string
+		protocol Protocol
+		colors   []color.RGBA
+		packets  [][]byte
+		err      error
+	}{
+		{
+			title:    "drgb",
+			protocol: DRGB,
+			colors:   colors,
+			packets:  [][]byte{{2, Timeout, 1, 2, 3, 4, 5, 6}},
+		},
+		{
+			title:    "drgb with too many leds",
+			protocol: DRGB,
+			colors:   many,
+			err:      ErrTooManyLEDs,
+		},
+		{
+			title:    "dnrgb",
+			protocol: DNRGB,
+			colors:   colors,
+			packets:  [][]byte{{4, Timeout, 0, 0, 1, 2, 3, 4, 5, 6}},
+		},
+		{
+			title:    "raw",
+			protocol: Raw,
+			colors:   colors,
+			packets:  [][]byte{{1, 2, 3, 4, 5, 6}},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+		t.Run(name, func(t *testing.T) {
+			packets, err := Packets(test.protocol, test.colors)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.packets, packets)
+		})
+	}
+
+	// Large strips are split across packets, each with its start index
+	packets, err := Packets(DNRGB, many)
+	require.Nil(t, err)
+	require.Len(t, packets, 2)
+	assert.Len(t, packets[0], 4+489*3)
+	assert.Len(t, packets[1], 4+11*3)
+	assert.Equal(t, []byte{4, Timeout, 0x01, 0xE9, 7, 8, 9}, packets[1][:7])
+}
+
+func TestSender(t *testing.T) {
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	sender, err := Dial(listener.LocalAddr().String(), Raw)
+	require.Nil(t, err)
+	defer sender.Close()
+
+	require.Nil(t, sender.Send([]color.RGBA{{1, 2, 3, 0xFF}}))
+
+	buf := make([]byte, 16)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	require.Nil(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, buf[:n])
+}
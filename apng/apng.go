@@ -0,0 +1,278 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package apng writes animated PNG images, as an alternative to animated GIFs
+// that many users now prefer for short animations. Decoders that do not
+// support animation show the first frame as a still image.
+//
+// Frames are written exactly as given. Quantizing or dithering each frame,
+// such as with draw.FloydSteinberg, is left to the caller, since a palette
+// cycling animation must keep the same pixels in every frame.
+package apng
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+	"math"
+)
+
+// The frame disposal methods, as numbered by the APNG format.
+const (
+	// DisposalNone leaves the frame in place when moving to the next one.
+	DisposalNone = 0
+
+	// DisposalBackground clears the area of the frame to transparent black
+	// when moving to the next one.
+	DisposalBackground = 1
+
+	// DisposalPrevious restores the area of the frame to what it was before
+	// the frame was drawn, when moving to the next one.
+	DisposalPrevious = 2
+)
+
+// The PNG color types written.
+const (
+	colorIndexed = 3
+	colorRGBA    = 6
+)
+
+// header is the magic number that every PNG image starts with.
+const header = "\x89PNG\r\n\x1a\n"
+
+// APNG is an animation, which mirrors the gif.GIF type so that animations can
+// be written in either format.
+type APNG struct {
+	// Image holds the frames of the animation. Every frame must lie within
+	// the bounds of the first.
+	Image []image.Image
+
+	// Delay holds the delay after each frame, in hundredths of a second.
+	Delay []int
+
+	// Disposal holds the disposal method of each frame. If nil, every frame
+	// is left in place.
+	Disposal []byte
+
+	// LoopCount is the number of times the animation is played, where zero
+	// plays it forever.
+	LoopCount int
+}
+
+// EncodeAll writes the given animation to the given writer. When every frame
+// is a paletted image sharing the same palette, the palette is written once
+// and every frame is indexed into it. Otherwise, every frame is written with
+// 8-bit RGBA pixels.
+func EncodeAll(w io.Writer, a *APNG) error {
+	if len(a.Image) == 0 {
+		return errors.New("apng: no frames")
+	}
+	if len(a.Delay) != len(a.Image) {
+		return errors.New("apng: mismatched image and delay lengths")
+	}
+	if a.Disposal != nil && len(a.Disposal) != len(a.Image) {
+		return errors.New("apng: mismatched image and disposal lengths")
+	}
+	if a.LoopCount < 0 {
+		return errors.New("apng: invalid loop count")
+	}
+
+	bounds := a.Image[0].Bounds()
+	if bounds.Empty() {
+		return errors.New("apng: empty first frame")
+	}
+	for _, frame := range a.Image {
+		if !frame.Bounds().In(bounds) || frame.Bounds().Empty() {
+			return errors.New("apng: frame not within the bounds of the first frame")
+		}
+	}
+
+	palette, shared := sharedPalette(a.Image)
+
+	e := encoder{w: w}
+	e.write([]byte(header))
+
+	var ihdr [13]byte
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(bounds.Dy()))
+	ihdr[8] = 8
+	ihdr[9] = colorRGBA
+	if shared {
+		ihdr[9] = colorIndexed
+	}
+	e.chunk("IHDR", ihdr[:])
+
+	var actl [8]byte
+	binary.BigEndian.PutUint32(actl[0:4], uint32(len(a.Image)))
+	binary.BigEndian.PutUint32(actl[4:8], uint32(a.LoopCount))
+	e.chunk("acTL", actl[:])
+
+	if shared {
+		e.palette(palette)
+	}
+
+	for index, frame := range a.Image {
+		var disposal byte
+		if a.Disposal != nil {
+			disposal = a.Disposal[index]
+		}
+		if disposal > DisposalPrevious {
+			return errors.New("apng: invalid disposal method")
+		}
+		if a.Delay[index] < 0 || a.Delay[index] > math.MaxUint16 {
+			return errors.New("apng: invalid delay")
+		}
+
+		rect := frame.Bounds()
+
+		var fctl [26]byte
+		binary.BigEndian.PutUint32(fctl[0:4], e.sequence)
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(rect.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(rect.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:16], uint32(rect.Min.X-bounds.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:20], uint32(rect.Min.Y-bounds.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:22], uint16(a.Delay[index]))
+		binary.BigEndian.PutUint16(fctl[22:24], 100)
+		fctl[24] = disposal
+		e.sequence++
+		e.chunk("fcTL", fctl[:])
+
+		data, err := pixels(frame, shared)
+		if err != nil {
+			return err
+		}
+
+		// The first frame doubles as the still image
+		if index == 0 {
+			e.chunk("IDAT", data)
+			continue
+		}
+
+		var sequence [4]byte
+		binary.BigEndian.PutUint32(sequence[:], e.sequence)
+		e.sequence++
+		e.chunk("fdAT", append(sequence[:], data...))
+	}
+
+	e.chunk("IEND", nil)
+
+	return e.err
+}
+
+// encoder writes PNG chunks, keeping the first error encountered.
+type encoder struct {
+	w        io.Writer
+	sequence uint32
+	err      error
+}
+
+func (e *encoder) write(data []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(data)
+	}
+}
+
+// chunk writes a single chunk of the given type, followed by its checksum.
+func (e *encoder) chunk(name string, data []byte) {
+	var length, checksum [4]byte
+
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(name))
+	crc.Write(data)
+	binary.BigEndian.PutUint32(checksum[:], crc.Sum32())
+
+	e.write(length[:])
+	e.write([]byte(name))
+	e.write(data)
+	e.write(checksum[:])
+}
+
+// palette writes the shared palette, along with the alpha of every color if
+// any are not opaque.
+func (e *encoder) palette(palette color.Palette) {
+	plte := make([]byte, 0, len(palette)*3)
+	trns := make([]byte, 0, len(palette))
+	opaque := true
+
+	for _, clr := range palette {
+		nrgba := color.NRGBAModel.Convert(clr).(color.NRGBA)
+		plte = append(plte, nrgba.R, nrgba.G, nrgba.B)
+		trns = append(trns, nrgba.A)
+		opaque = opaque && nrgba.A == 0xFF
+	}
+
+	e.chunk("PLTE", plte)
+	if !opaque {
+		e.chunk("tRNS", trns)
+	}
+}
+
+// sharedPalette returns the palette of the given frames, if they are all
+// paletted images with an identical palette that PNG can hold.
+func sharedPalette(frames []image.Image) (color.Palette, bool) {
+	first, ok := frames[0].(*image.Paletted)
+	if !ok || len(first.Palette) == 0 || len(first.Palette) > 256 {
+		return nil, false
+	}
+
+	for _, frame := range frames[1:] {
+		paletted, ok := frame.(*image.Paletted)
+		if !ok || len(paletted.Palette) != len(first.Palette) {
+			return nil, false
+		}
+
+		for index, clr := range paletted.Palette {
+			if color.NRGBAModel.Convert(clr) != color.NRGBAModel.Convert(first.Palette[index]) {
+				return nil, false
+			}
+		}
+	}
+
+	return first.Palette, true
+}
+
+// pixels returns the compressed pixels of the given frame, as either palette
+// indices or 8-bit RGBA pixels, with each row unfiltered.
+func pixels(frame image.Image, indexed bool) ([]byte, error) {
+	rect := frame.Bounds()
+
+	var buf bytes.Buffer
+	writer := zlib.NewWriter(&buf)
+
+	depth := 4
+	if indexed {
+		depth = 1
+	}
+	row := make([]byte, 1+rect.Dx()*depth)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		if indexed {
+			paletted := frame.(*image.Paletted)
+			offset := paletted.PixOffset(rect.Min.X, y)
+			copy(row[1:], paletted.Pix[offset:offset+rect.Dx()])
+		} else {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				clr := color.NRGBAModel.Convert(frame.At(x, y)).(color.NRGBA)
+				copy(row[1+(x-rect.Min.X)*4:], []byte{clr.R, clr.G, clr.B, clr.A})
+			}
+		}
+
+		if _, err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
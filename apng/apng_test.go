@@ -0,0 +1,155 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package apng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 0xFF}
+	green = color.RGBA{0, 255, 0, 0xFF}
+	blue  = color.RGBA{0, 0, 255, 0xFF}
+)
+
+// chunks returns the type of every chunk within the given PNG image.
+func chunks(t *testing.T, data []byte) []string {
+	require.True(t, bytes.HasPrefix(data, []byte(header)))
+	data = data[len(header):]
+
+	var names []string
+	for len(data) > 0 {
+		require.True(t, len(data) >= 12)
+		length := int(binary.BigEndian.Uint32(data[:4]))
+		names = append(names, string(data[4:8]))
+		data = data[12+length:]
+	}
+
+	return names
+}
+
+func TestEncodeAll(t *testing.T) {
+
+	paletted := func(palette color.Palette) *image.Paletted {
+		img := image.NewPaletted(image.Rect(0, 0, 2, 1), palette)
+		img.Pix = []uint8{0, 1}
+		return img
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	rgba.SetRGBA(0, 0, red)
+	rgba.SetRGBA(1, 0, green)
+
+	tests := []struct {
+		title  string
+		frames []image.Image
+		chunks []string
+	}{
+		{
+			title:  "single frame",
+			frames: []image.Image{rgba},
+			chunks: []string{"IHDR", "acTL", "fcTL", "IDAT", "IEND"},
+		},
+		{
+			title: "shared palette",
+			frames: []image.Image{
+				paletted(color.Palette{red, green}),
+				paletted(color.Palette{red, green}),
+			},
+			chunks: []string{"IHDR", "acTL", "PLTE", "fcTL", "IDAT", "fcTL", "fdAT", "IEND"},
+		},
+		{
+			title: "distinct palettes",
+			frames: []image.Image{
+				paletted(color.Palette{red, green}),
+				paletted(color.Palette{green, blue}),
+			},
+			chunks: []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "IEND"},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			require.Nil(t, EncodeAll(&buf, &APNG{
+				Image: test.frames,
+				Delay: make([]int, len(test.frames)),
+			}))
+
+			assert.Equal(t, test.chunks, chunks(t, buf.Bytes()))
+
+			// Decoders without animation support show the first frame
+			img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+			require.Nil(t, err)
+
+			for x := 0; x < 2; x++ {
+				expected := color.NRGBAModel.Convert(test.frames[0].At(x, 0))
+				assert.Equal(t, expected, color.NRGBAModel.Convert(img.At(x, 0)))
+			}
+
+		})
+	}
+
+}
+
+func TestEncodeAllInvalid(t *testing.T) {
+
+	frame := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	outside := image.NewRGBA(image.Rect(1, 1, 3, 3))
+
+	tests := []struct {
+		title     string
+		animation APNG
+	}{
+		{
+			title:     "no frames",
+			animation: APNG{},
+		},
+		{
+			title:     "missing delay",
+			animation: APNG{Image: []image.Image{frame}},
+		},
+		{
+			title:     "missing disposal",
+			animation: APNG{Image: []image.Image{frame}, Delay: []int{0}, Disposal: []byte{}},
+		},
+		{
+			title:     "invalid disposal",
+			animation: APNG{Image: []image.Image{frame}, Delay: []int{0}, Disposal: []byte{3}},
+		},
+		{
+			title:     "invalid delay",
+			animation: APNG{Image: []image.Image{frame}, Delay: []int{-1}},
+		},
+		{
+			title:     "frame outside bounds",
+			animation: APNG{Image: []image.Image{frame, outside}, Delay: []int{0, 0}},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			assert.NotNil(t, EncodeAll(&buf, &test.animation))
+
+		})
+	}
+
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+const (
+	// backgroundBorder is the smallest fraction of the border pixels of an
+	// image that a background color covers.
+	backgroundBorder = 0.25
+
+	// backgroundRegion is the smallest fraction of an image that the largest
+	// region of a background color covers.
+	backgroundRegion = 0.05
+)
+
+// Background takes in an image and a palette, and reports whether each palette
+// color, in the same order, is likely part of the background rather than the
+// foreground. Every pixel is remapped to its nearest palette color, and a
+// color is considered background when it covers a large share of the border
+// of the image, and also forms a large connected region rather than scattered
+// specks.
+func Background(img image.Image, colors []color.RGBA) []bool {
+	background := make([]bool, len(colors))
+
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	if len(colors) == 0 || width == 0 || height == 0 {
+		return background
+	}
+
+	indices := remapIndices(img, colors)
+
+	// Count the pixels along every edge of the image once
+	borders := make([]int, len(colors))
+	var total int
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				borders[indices[y*width+x]]++
+				total++
+			}
+		}
+	}
+
+	regions := Regions(img, colors)
+
+	for index := range colors {
+		border := float64(borders[index]) / float64(total)
+		region := float64(regions[index].Largest) / float64(width*height)
+
+		background[index] = border >= backgroundBorder && region >= backgroundRegion
+	}
+
+	return background
+}
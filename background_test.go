@@ -0,0 +1,86 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackground(t *testing.T) {
+
+	tests := []struct {
+		title      string
+		img        image.Image
+		colors     []color.RGBA
+		background []bool
+	}{
+		{
+			title:      "no colors",
+			img:        testImage(2, 2, func(int, int) color.RGBA { return red }),
+			colors:     []color.RGBA{},
+			background: []bool{},
+		},
+		{
+			title: "subject in the middle",
+			img: testImage(10, 10, func(x int, y int) color.RGBA {
+				if x >= 3 && x < 7 && y >= 3 && y < 7 {
+					return red
+				}
+				return white
+			}),
+			colors:     []color.RGBA{red, white, blue},
+			background: []bool{false, true, false},
+		},
+		{
+			title: "subject against the bottom",
+			img: testImage(10, 10, func(x int, y int) color.RGBA {
+				if x >= 2 && x < 8 && y >= 4 {
+					return red
+				}
+				return white
+			}),
+			colors:     []color.RGBA{red, white},
+			background: []bool{false, true},
+		},
+		{
+			title: "sky and ground",
+			img: testImage(10, 10, func(_ int, y int) color.RGBA {
+				if y < 5 {
+					return blue
+				}
+				return green
+			}),
+			colors:     []color.RGBA{blue, green},
+			background: []bool{true, true},
+		},
+		{
+			title: "speckled border",
+			img: testImage(20, 20, func(x int, y int) color.RGBA {
+				if (x+y)%2 == 0 {
+					return red
+				}
+				return white
+			}),
+			colors:     []color.RGBA{red, white},
+			background: []bool{false, false},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.background, Background(test.img, test.colors))
+
+		})
+	}
+
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// box is a partition of pixels, along with the weight of each pixel if the
+// pixels are weighted.
+type box struct {
+	pixels  []color.RGBA
+	weights []float64
+
+	// population is the number of pixels, or their total weight if weighted.
+	population float64
+}
+
+// newBox returns a box holding the given pixels, which are weighted unless
+// there are no weights.
+func newBox(pixels []color.RGBA, weights []float64) box {
+	if len(weights) == 0 {
+		return box{pixels: pixels, population: float64(len(pixels))}
+	}
+
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	return box{pixels, weights, total}
+}
+
+// average returns the average color of the box, weighted if the box is.
+func (b box) average(truncate bool) color.RGBA {
+	if b.weights == nil {
+		return averageOf(b.pixels, truncate)
+	}
+
+	var totalR, totalG, totalB float64
+	for index, pixel := range b.pixels {
+		weight := b.weights[index]
+		totalR += weight * float64(pixel.R)
+		totalG += weight * float64(pixel.G)
+		totalB += weight * float64(pixel.B)
+	}
+
+	// Adding a half rounds the quotient to the nearest value
+	var half float64
+	if !truncate {
+		half = 0.5
+	}
+
+	return color.RGBA{
+		uint8(math.Min(math.Floor(totalR/b.population+half), 255)),
+		uint8(math.Min(math.Floor(totalG/b.population+half), 255)),
+		uint8(math.Min(math.Floor(totalB/b.population+half), 255)),
+		0xFF,
+	}
+}
+
+// split bisects the given box, unless it is exhausted because it holds fewer
+// than two pixels, or only a single distinct color. Weighted boxes are split
+// at their weighted median.
+func split(partition box) (box, box, bool) {
+
+	if len(partition.pixels) < 2 {
+		return box{}, box{}, false
+	}
+
+	deltaR, deltaG, deltaB := Spread(partition.pixels)
+	if deltaR == 0 && deltaG == 0 && deltaB == 0 {
+		return box{}, box{}, false
+	}
+
+	if partition.weights == nil {
+		left, right := bisect(partition.pixels, deltaR, deltaG, deltaB)
+		return newBox(left, nil), newBox(right, nil), true
+	}
+
+	sort.Stable(weightedPixels{partition, component(deltaR, deltaG, deltaB)})
+
+	// Cut after the last pixel that keeps the left half within half of the
+	// total weight, which matches bisect when every weight is equal
+	cut := 1
+	var total float64
+	for index := 1; index < len(partition.weights); index++ {
+		total += partition.weights[index-1]
+		if 2*total > partition.population {
+			break
+		}
+		cut = index
+	}
+
+	return newBox(partition.pixels[:cut], partition.weights[:cut]),
+		newBox(partition.pixels[cut:], partition.weights[cut:]), true
+}
+
+// component returns a function that selects the color component with the
+// largest of the given spreads, breaking ties in the same order as bisect.
+func component(deltaR uint8, deltaG uint8, deltaB uint8) func(color.RGBA) uint8 {
+	switch {
+	case deltaR >= deltaG && deltaR >= deltaB:
+		return func(c color.RGBA) uint8 { return c.R }
+	case deltaG >= deltaR && deltaG >= deltaB:
+		return func(c color.RGBA) uint8 { return c.G }
+	default:
+		return func(c color.RGBA) uint8 { return c.B }
+	}
+}
+
+// weightedPixels sorts the pixels of a weighted box by a single color
+// component, keeping their weights alongside them.
+type weightedPixels struct {
+	box
+	key func(color.RGBA) uint8
+}
+
+func (w weightedPixels) Len() int {
+	return len(w.pixels)
+}
+
+func (w weightedPixels) Less(i int, j int) bool {
+	return w.key(w.pixels[i]) < w.key(w.pixels[j])
+}
+
+func (w weightedPixels) Swap(i int, j int) {
+	w.pixels[i], w.pixels[j] = w.pixels[j], w.pixels[i]
+	w.weights[i], w.weights[j] = w.weights[j], w.weights[i]
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWeighted(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		pixels  []color.RGBA
+		weights []float64
+		left    []color.RGBA
+		right   []color.RGBA
+		ok      bool
+	}{
+		{
+			title:   "single pixel",
+			pixels:  []color.RGBA{{255, 0, 0, 0xFF}},
+			weights: []float64{1},
+		},
+		{
+			title:   "single color",
+			pixels:  []color.RGBA{{255, 0, 0, 0xFF}, {255, 0, 0, 0xFF}},
+			weights: []float64{1, 5},
+		},
+		{
+			title:   "equal weights",
+			pixels:  []color.RGBA{{30, 0, 0, 0xFF}, {10, 0, 0, 0xFF}, {20, 0, 0, 0xFF}},
+			weights: []float64{1, 1, 1},
+			left:    []color.RGBA{{10, 0, 0, 0xFF}},
+			right:   []color.RGBA{{20, 0, 0, 0xFF}, {30, 0, 0, 0xFF}},
+			ok:      true,
+		},
+		{
+			title:   "heavy last pixel",
+			pixels:  []color.RGBA{{0, 0, 10, 0xFF}, {0, 0, 20, 0xFF}, {0, 0, 30, 0xFF}},
+			weights: []float64{1, 1, 10},
+			left:    []color.RGBA{{0, 0, 10, 0xFF}, {0, 0, 20, 0xFF}},
+			right:   []color.RGBA{{0, 0, 30, 0xFF}},
+			ok:      true,
+		},
+		{
+			title:   "heavy first pixel",
+			pixels:  []color.RGBA{{0, 10, 0, 0xFF}, {0, 20, 0, 0xFF}, {0, 30, 0, 0xFF}},
+			weights: []float64{10, 1, 1},
+			left:    []color.RGBA{{0, 10, 0, 0xFF}},
+			right:   []color.RGBA{{0, 20, 0, 0xFF}, {0, 30, 0, 0xFF}},
+			ok:      true,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			left, right, ok := split(newBox(test.pixels, test.weights))
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.left, left.pixels)
+			assert.Equal(t, test.right, right.pixels)
+
+		})
+	}
+
+}
+
+func TestBoxAverage(t *testing.T) {
+
+	pixels := []color.RGBA{{0, 0, 0, 0xFF}, {255, 255, 255, 0xFF}}
+
+	assert.Equal(t, color.RGBA{128, 128, 128, 0xFF}, newBox(pixels, nil).average(false))
+	assert.Equal(t, color.RGBA{128, 128, 128, 0xFF}, newBox(pixels, []float64{1, 1}).average(false))
+	assert.Equal(t, color.RGBA{127, 127, 127, 0xFF}, newBox(pixels, []float64{1, 1}).average(true))
+	assert.Equal(t, color.RGBA{191, 191, 191, 0xFF}, newBox(pixels, []float64{1, 3}).average(false))
+
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// ErrInvalidBuffer is returned when the layout of a raw pixel buffer is
+// invalid, or the buffer is too small to hold every pixel.
+var ErrInvalidBuffer = errors.New("invalid pixel buffer")
+
+// RGBBuffer takes in raw 8-bit pixels with the given number of bands, either 3
+// for RGB or 4 for RGBA, as exported by libvips & other image processing
+// libraries, and performs MMCQ to the specified number of levels. Rows are
+// stride bytes apart, and any alpha band is ignored. The pixels are ordered
+// exactly as Image would order them, so that both produce identical palettes
+// for the same image. Levels outside of [0, MaxLevels] are clamped. Returns
+// ErrInvalidBuffer if the buffer does not match its layout.
+func RGBBuffer(buf []byte, width int, height int, stride int, bands int, levels int) ([]color.RGBA, error) {
+	var quantizer Quantizer
+	return quantizer.RGBBuffer(buf, width, height, stride, bands, clampLevels(levels))
+}
+
+// RGBBuffer takes in raw 8-bit pixels with the given number of bands, and
+// performs MMCQ to the specified number of levels. Pixels are passed to the
+// pixel filter & weight with their coordinates, as if the top left pixel were
+// at the origin. Returns ErrInvalidLevels if levels is not within
+// [0, MaxLevels], and ErrInvalidBuffer if the buffer does not match its
+// layout.
+func (q *Quantizer) RGBBuffer(buf []byte, width int, height int, stride int, bands int, levels int) ([]color.RGBA, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return nil, ErrInvalidLevels
+	}
+
+	if err := q.extractBuffer(buf, width, height, stride, bands); err != nil {
+		return nil, err
+	}
+
+	return q.quantize(q.pixels, q.weights, levels, 1<<uint(levels)), nil
+}
+
+// Raw takes in raw 8-bit RGBA pixels, such as a framebuffer or a GPU
+// readback, with rows stride bytes apart, and performs MMCQ until the palette
+// holds exactly n colors, like Colors. The buffer is read in place, without
+// being wrapped in an image.Image, and the alpha component is ignored. A number
+// of colors outside of [1, MaxColors] is clamped. Returns ErrInvalidBuffer if
+// the buffer is too small.
+func Raw(buf []byte, width int, height int, stride int, n int) ([]color.RGBA, error) {
+	switch {
+	case n < 1:
+		n = 1
+	case n > MaxColors:
+		n = MaxColors
+	}
+
+	var quantizer Quantizer
+	return quantizer.Raw(buf, width, height, stride, n)
+}
+
+// Raw takes in raw 8-bit RGBA pixels with rows stride bytes apart, and
+// performs MMCQ until the palette holds exactly n colors. Returns
+// ErrInvalidColors if n is not within [1, MaxColors], and ErrInvalidBuffer if
+// the buffer is too small.
+func (q *Quantizer) Raw(buf []byte, width int, height int, stride int, n int) ([]color.RGBA, error) {
+
+	if n < 1 || n > MaxColors {
+		return nil, ErrInvalidColors
+	}
+
+	if err := q.extractBuffer(buf, width, height, stride, 4); err != nil {
+		return nil, err
+	}
+
+	return q.quantize(q.pixels, q.weights, 0, n), nil
+}
+
+// extractBuffer fills the pixel buffer with the pixels of the given raw
+// buffer, column by column, and which pass the pixel filter. If the pixels are
+// weighted, the weight buffer is filled with the weight of each pixel.
+func (q *Quantizer) extractBuffer(buf []byte, width int, height int, stride int, bands int) error {
+
+	if bands != 3 && bands != 4 {
+		return ErrInvalidBuffer
+	}
+	if width < 0 || height < 0 || stride < width*bands {
+		return ErrInvalidBuffer
+	}
+	if width > 0 && height > 0 && len(buf) < stride*(height-1)+width*bands {
+		return ErrInvalidBuffer
+	}
+
+	q.phase(phaseExtract, func() {
+		if count := width * height; cap(q.pixels) < count {
+			q.pixels = make([]color.RGBA, 0, count)
+		}
+		pixels := q.pixels[:0]
+
+		for x := 0; x < width; x++ {
+			for offset := x * bands; offset < height*stride; offset += stride {
+				pixels = append(pixels, color.RGBA{buf[offset], buf[offset+1], buf[offset+2], 0xFF})
+			}
+		}
+
+		q.pixels = pixels
+		q.retain(image.Rect(0, 0, width, height))
+	})
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packBuffer packs the pixels of the given image into a raw buffer with the
+// given number of bands, and the given padding at the end of every row.
+func packBuffer(img *image.RGBA, bands int, padding int) ([]byte, int) {
+	rect := img.Bounds()
+	stride := rect.Dx()*bands + padding
+	buf := make([]byte, stride*rect.Dy())
+
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			pixel := img.RGBAAt(rect.Min.X+x, rect.Min.Y+y)
+			copy(buf[y*stride+x*bands:], []byte{pixel.R, pixel.G, pixel.B, 0x7F}[:bands])
+		}
+	}
+
+	return buf, stride
+}
+
+func TestRGBBuffer(t *testing.T) {
+
+	img := testImage(13, 7, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 19), uint8(y * 37), uint8(x * y * 3), 0xFF}
+	})
+
+	tests := []struct {
+		title   string
+		bands   int
+		padding int
+	}{
+		{
+			title: "rgb",
+			bands: 3,
+		},
+		{
+			title:   "rgb with padded rows",
+			bands:   3,
+			padding: 5,
+		},
+		{
+			title:   "rgba",
+			bands:   4,
+			padding: 8,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			buf, stride := packBuffer(img, test.bands, test.padding)
+
+			// The last row need not be padded
+			buf = buf[:len(buf)-test.padding]
+
+			colors, err := RGBBuffer(buf, 13, 7, stride, test.bands, 3)
+			require.Nil(t, err)
+			assert.Equal(t, Image(img, 3), colors)
+
+		})
+	}
+}
+
+func TestRGBBufferFilter(t *testing.T) {
+
+	img := testImage(4, 4, func(x int, _ int) color.RGBA {
+		if x < 2 {
+			return red
+		}
+		return blue
+	})
+	buf, stride := packBuffer(img, 3, 0)
+
+	// The filter is given the coordinates of every pixel
+	quantizer := NewQuantizer(WithPixelFilter(func(x int, _ int, _ color.RGBA) bool {
+		return x >= 2
+	}))
+
+	colors, err := quantizer.RGBBuffer(buf, 4, 4, stride, 3, 0)
+	require.Nil(t, err)
+	assert.Equal(t, []color.RGBA{blue}, colors)
+}
+
+func TestRGBBufferInvalid(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		size   int
+		width  int
+		height int
+		stride int
+		bands  int
+		err    error
+	}{
+		{
+			title:  "unsupported bands",
+			size:   8,
+			width:  4,
+			height: 1,
+			stride: 8,
+			bands:  2,
+			err:    ErrInvalidBuffer,
+		},
+		{
+			title:  "stride too small",
+			size:   12,
+			width:  4,
+			height: 1,
+			stride: 8,
+			bands:  3,
+			err:    ErrInvalidBuffer,
+		},
+		{
+			title:  "buffer too small",
+			size:   23,
+			width:  4,
+			height: 2,
+			stride: 12,
+			bands:  3,
+			err:    ErrInvalidBuffer,
+		},
+		{
+			title:  "negative size",
+			width:  -1,
+			height: 1,
+			bands:  3,
+			err:    ErrInvalidBuffer,
+		},
+		{
+			title: "empty",
+			bands: 4,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			_, err := RGBBuffer(make([]byte, test.size), test.width, test.height, test.stride, test.bands, 1)
+			assert.Equal(t, test.err, err)
+
+		})
+	}
+}
+
+func TestRaw(t *testing.T) {
+
+	img := testImage(9, 6, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 27), uint8(y * 41), 0x80, 0xFF}
+	})
+
+	// An RGBA image is its own raw buffer
+	for _, n := range []int{1, 3, 5} {
+		colors, err := Raw(img.Pix, 9, 6, img.Stride, n)
+		require.Nil(t, err)
+		assert.Equal(t, Colors(img, n), colors)
+	}
+
+	// A sub image shares the buffer of its parent, offset to its first pixel
+	sub := img.SubImage(image.Rect(2, 1, 7, 5)).(*image.RGBA)
+	colors, err := Raw(sub.Pix, 5, 4, sub.Stride, 4)
+	require.Nil(t, err)
+	assert.Equal(t, Colors(sub, 4), colors)
+
+	_, err = NewQuantizer().Raw(img.Pix, 9, 6, img.Stride, 0)
+	assert.Equal(t, ErrInvalidColors, err)
+
+	_, err = Raw(img.Pix[:10], 9, 6, img.Stride, 4)
+	assert.Equal(t, ErrInvalidBuffer, err)
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image/color"
+	"sort"
+)
+
+// Builder accumulates an image one row at a time, for callers with custom
+// decoders (raw camera data, framebuffers, video frames) that never construct
+// an image.Image. The zero value is ready to use.
+type Builder struct {
+	rows map[int][]color.RGBA
+}
+
+// AddRow adds the row of pixels at the given y coordinate, given as packed
+// 8-bit RGBA values. Any trailing bytes that do not form a complete pixel are
+// ignored, as is the alpha component. Adding the same row twice replaces it.
+func (b *Builder) AddRow(y int, rgba []uint8) {
+	if b.rows == nil {
+		b.rows = map[int][]color.RGBA{}
+	}
+
+	row := make([]color.RGBA, len(rgba)/4)
+	for x := range row {
+		row[x] = color.RGBA{rgba[x*4], rgba[x*4+1], rgba[x*4+2], 0xFF}
+	}
+
+	b.rows[y] = row
+}
+
+// Pixels returns every pixel added so far, ordered exactly as Image would
+// order them, so that both produce identical palettes for the same image.
+func (b *Builder) Pixels() []color.RGBA {
+
+	ys := make([]int, 0, len(b.rows))
+	width, count := 0, 0
+
+	for y, row := range b.rows {
+		ys = append(ys, y)
+		width = maxInt(width, len(row))
+		count += len(row)
+	}
+
+	sort.Ints(ys)
+	pixels := make([]color.RGBA, 0, count)
+
+	// Pixels are ordered by column, and then by row
+	for x := 0; x < width; x++ {
+		for _, y := range ys {
+			if row := b.rows[y]; x < len(row) {
+				pixels = append(pixels, row[x])
+			}
+		}
+	}
+
+	return pixels
+}
+
+// Quantize performs MMCQ on every pixel added so far, to the specified number
+// of levels.
+func (b *Builder) Quantize(levels int) []color.RGBA {
+	return Pixels(b.Pixels(), levels)
+}
+
+// Reset discards every pixel added so far.
+func (b *Builder) Reset() {
+	b.rows = nil
+}
+
+func maxInt(first int, second int) int {
+	if first > second {
+		return first
+	}
+	return second
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		rows   map[int][]uint8
+		pixels []color.RGBA
+	}{
+		{
+			title:  "no rows",
+			rows:   map[int][]uint8{},
+			pixels: []color.RGBA{},
+		},
+		{
+			title: "ignore alpha",
+			rows: map[int][]uint8{
+				0: {105, 32, 165, 0}, // random values
+			},
+			pixels: []color.RGBA{
+				{105, 32, 165, 0xFF},
+			},
+		},
+		{
+			title: "ignore partial pixel",
+			rows: map[int][]uint8{
+				0: {105, 32, 165, 0xFF, 1, 2},
+			},
+			pixels: []color.RGBA{
+				{105, 32, 165, 0xFF},
+			},
+		},
+		{
+			title: "column major order",
+			rows: map[int][]uint8{
+				1: {3, 3, 3, 0xFF, 4, 4, 4, 0xFF},
+				0: {1, 1, 1, 0xFF, 2, 2, 2, 0xFF},
+			},
+			pixels: []color.RGBA{
+				{1, 1, 1, 0xFF},
+				{3, 3, 3, 0xFF},
+				{2, 2, 2, 0xFF},
+				{4, 4, 4, 0xFF},
+			},
+		},
+		{
+			title: "ragged rows",
+			rows: map[int][]uint8{
+				5: {1, 1, 1, 0xFF},
+				9: {2, 2, 2, 0xFF, 3, 3, 3, 0xFF},
+			},
+			pixels: []color.RGBA{
+				{1, 1, 1, 0xFF},
+				{2, 2, 2, 0xFF},
+				{3, 3, 3, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var builder Builder
+
+			for y, row := range test.rows {
+				builder.AddRow(y, row)
+			}
+
+			assert.Equal(t, test.pixels, builder.Pixels())
+
+			builder.Reset()
+			assert.Equal(t, []color.RGBA{}, builder.Pixels())
+
+		})
+	}
+
+}
+
+func TestBuilderMatchesImage(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.png"))
+	require.Nil(t, err)
+	defer func() {
+		if err := file.Close(); err != nil {
+			panic(err.Error())
+		}
+	}()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	rect := img.Bounds()
+	rgba := image.NewRGBA(rect)
+	draw.Draw(rgba, rect, img, rect.Min, draw.Src)
+
+	var builder Builder
+
+	// Feed scanlines in reverse, as some decoders produce bottom-up images
+	for y := rect.Max.Y - 1; y >= rect.Min.Y; y-- {
+		offset := rgba.PixOffset(rect.Min.X, y)
+		builder.AddRow(y, rgba.Pix[offset:offset+rect.Dx()*4])
+	}
+
+	assert.Equal(t, Image(img, 3), builder.Quantize(3))
+
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	// Register the remaining standard formats, so that Bytes works out of the
+	// box
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	// DefaultMaxDimension is the largest width or height of an image that
+	// Bytes will decode, unless overridden with WithDecodeLimits.
+	DefaultMaxDimension = 16384
+
+	// DefaultMaxPixels is the largest number of pixels in an image that Bytes
+	// will decode, unless overridden with WithDecodeLimits.
+	DefaultMaxPixels = 50000000
+)
+
+// ErrImageTooLarge is returned when an image exceeds the decode limits.
+var ErrImageTooLarge = errors.New("image exceeds decode limits")
+
+// WithDecodeLimits sets the largest width, height, and number of pixels of an
+// image that Bytes will decode. A limit of zero keeps the default.
+func WithDecodeLimits(maxWidth int, maxHeight int, maxPixels int) Option {
+	return func(q *Quantizer) {
+		q.maxWidth, q.maxHeight, q.maxPixels = maxWidth, maxHeight, maxPixels
+	}
+}
+
+// Bytes takes in an encoded GIF, JPEG, or PNG image (or any other format
+// registered with the image package), and performs MMCQ to the specified
+// number of levels. The image header is checked against the default decode
+// limits before the image is fully decoded, so that untrusted uploads cannot
+// exhaust memory.
+func Bytes(data []byte, levels int) ([]color.RGBA, error) {
+	var quantizer Quantizer
+	return quantizer.Bytes(data, levels)
+}
+
+// Bytes takes in an encoded image, and performs MMCQ to the specified number
+// of levels. The image header is checked against the decode limits before the
+// image is fully decoded, as is the header of any EXIF thumbnail that is
+// quantized instead. Returns ErrImageTooLarge if any limit is exceeded.
+func (q *Quantizer) Bytes(data []byte, levels int) ([]color.RGBA, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return nil, ErrInvalidLevels
+	}
+
+	// Thumbnails that can not be decoded are ignored in favor of the image
+	if q.thumbnail {
+		if thumbnail, found := exifThumbnail(data); found {
+			if config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail)); err == nil && q.within(config) {
+				if img, err := jpeg.Decode(bytes.NewReader(thumbnail)); err == nil {
+					return q.Image(img, levels)
+				}
+			}
+		}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if !q.within(config) {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Image(img, levels)
+}
+
+// within reports whether an image with the given header is within the decode
+// limits.
+func (q *Quantizer) within(config image.Config) bool {
+	maxWidth := limit(q.maxWidth, DefaultMaxDimension)
+	maxHeight := limit(q.maxHeight, DefaultMaxDimension)
+	maxPixels := limit(q.maxPixels, DefaultMaxPixels)
+
+	// Compare using 64 bits, since the product may overflow on 32 bit platforms
+	return config.Width <= maxWidth && config.Height <= maxHeight &&
+		int64(config.Width)*int64(config.Height) <= int64(maxPixels)
+}
+
+// limit returns the given limit, or the fallback if the limit is not set.
+func limit(value int, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.Nil(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestBytes(t *testing.T) {
+
+	for index, file := range []string{"plush.jpg", "plush.png", "plush.gif"} {
+		name := fmt.Sprintf("Case #%d - %s", index, file)
+
+		t.Run(name, func(t *testing.T) {
+
+			data, err := ioutil.ReadFile(path.Join("testdata", file))
+			require.Nil(t, err)
+
+			img, _, err := image.Decode(bytes.NewReader(data))
+			require.Nil(t, err)
+
+			palette, err := Bytes(data, 3)
+			require.Nil(t, err)
+
+			assert.Equal(t, Image(img, 3), palette)
+
+		})
+	}
+
+}
+
+func TestBytesInvalid(t *testing.T) {
+
+	solid := func(x int, y int) color.RGBA {
+		return color.RGBA{0x13, 0x25, 0x5c, 0xFF}
+	}
+
+	tests := []struct {
+		title     string
+		data      []byte
+		options   []Option
+		levels    int
+		err       error
+		formatErr bool
+	}{
+		{
+			title:     "not an image",
+			data:      []byte("hello world"),
+			levels:    3,
+			formatErr: true,
+		},
+		{
+			title:  "invalid levels",
+			data:   encodePNG(t, testImage(4, 4, solid)),
+			levels: -1,
+			err:    ErrInvalidLevels,
+		},
+		{
+			title:  "too wide",
+			data:   encodePNG(t, image.NewGray(image.Rect(0, 0, DefaultMaxDimension+1, 1))),
+			levels: 3,
+			err:    ErrImageTooLarge,
+		},
+		{
+			title:   "too many pixels",
+			data:    encodePNG(t, testImage(10, 10, solid)),
+			options: []Option{WithDecodeLimits(0, 0, 99)},
+			levels:  3,
+			err:     ErrImageTooLarge,
+		},
+		{
+			title:   "too tall",
+			data:    encodePNG(t, testImage(10, 10, solid)),
+			options: []Option{WithDecodeLimits(10, 9, 0)},
+			levels:  3,
+			err:     ErrImageTooLarge,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palette, err := NewQuantizer(test.options...).Bytes(test.data, test.levels)
+
+			assert.Nil(t, palette)
+			if test.formatErr {
+				assert.Equal(t, image.ErrFormat, err)
+			} else {
+				assert.Equal(t, test.err, err)
+			}
+
+		})
+	}
+
+	palette, err := NewQuantizer(WithDecodeLimits(10, 10, 100)).Bytes(encodePNG(t, testImage(10, 10, solid)), 1)
+	require.Nil(t, err)
+	assert.Equal(t, []color.RGBA{solid(0, 0), solid(0, 0)}, palette)
+
+}
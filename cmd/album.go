@@ -0,0 +1,53 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joshdk/quantize"
+)
+
+func albumCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize album", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the colors as a JSON object")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("image file not specified")))
+	}
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	album := quantize.Album(img)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(map[string]string{
+			"primary":   quantize.Hex(album.Primary),
+			"secondary": quantize.Hex(album.Secondary),
+			"text":      quantize.Hex(album.Text),
+		}); err != nil {
+			die(err)
+		}
+		return
+	}
+
+	fmt.Printf("primary %s\n", quantize.Hex(album.Primary))
+	fmt.Printf("secondary %s\n", quantize.Hex(album.Secondary))
+	fmt.Printf("text %s\n", quantize.Hex(album.Text))
+
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"net"
+	"strconv"
+
+	"github.com/joshdk/quantize"
+	"github.com/joshdk/quantize/ambilight"
+)
+
+// ambilightProtocols maps the name of every LED protocol to its value.
+var ambilightProtocols = map[string]ambilight.Protocol{
+	"drgb":  ambilight.DRGB,
+	"dnrgb": ambilight.DNRGB,
+	"raw":   ambilight.Raw,
+}
+
+// ambilightOutput holds the flags for pushing edge colors to LEDs.
+type ambilightOutput struct {
+	address   *string
+	protocol  *string
+	leds      *int
+	thickness *float64
+}
+
+// ambilightFlags registers the flags for pushing edge colors to LEDs with the
+// given flag set.
+func ambilightFlags(flags *flag.FlagSet) ambilightOutput {
+	return ambilightOutput{
+		address:   flags.String("udp", "", "push the colors along the edges to the WLED or Hyperion device at the given UDP address instead of printing palettes"),
+		protocol:  flags.String("protocol", "drgb", "UDP protocol, one of drgb, dnrgb, or raw for Hyperion"),
+		leds:      flags.Int("leds", 16, "number of LEDs along each edge"),
+		thickness: flags.Float64("thickness", 0.1, "thickness of the sampled strip along each edge, as a fraction of the image"),
+	}
+}
+
+// dial returns a sender for the requested device, or nil if none was given.
+func (a ambilightOutput) dial() *ambilight.Sender {
+	if *a.address == "" {
+		return nil
+	}
+
+	protocol, found := ambilightProtocols[*a.protocol]
+	if !found {
+		die(usageError(fmt.Errorf("unknown protocol %q", *a.protocol)))
+	}
+	if *a.leds <= 0 {
+		die(usageError(fmt.Errorf("invalid number of LEDs %d", *a.leds)))
+	}
+
+	// WLED listens on a well known port
+	address := *a.address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, strconv.Itoa(ambilight.DefaultPort))
+	}
+
+	sender, err := ambilight.Dial(address, protocol)
+	if err != nil {
+		die(err)
+	}
+
+	return sender
+}
+
+// send pushes the average color of every segment along the edges of the given
+// image to the device.
+func (a ambilightOutput) send(sender *ambilight.Sender, img image.Image) {
+	t := *a.thickness
+	edges := quantize.Edges(img, quantize.EdgeConfig{
+		Top:      t,
+		Bottom:   t,
+		Left:     t,
+		Right:    t,
+		Segments: *a.leds,
+	}, 0)
+
+	if err := sender.Send(ambilight.Colors(edges)); err != nil {
+		warn(err)
+	}
+}
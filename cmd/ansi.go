@@ -0,0 +1,74 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"strconv"
+)
+
+// defaultColumns is the terminal width assumed when it cannot be determined.
+const defaultColumns = 80
+
+// renderANSI prints the given image, remapped to the given palette, as
+// half-block characters colored with 24-bit ANSI escape codes. Each character
+// covers two rows of pixels, with the top pixel as the foreground color and
+// the bottom pixel as the background color.
+func renderANSI(img image.Image, colors []color.RGBA) {
+	out := bufio.NewWriter(os.Stdout)
+	remapped := remap(thumbnail(img, terminalColumns()), colors)
+	rect := remapped.Bounds()
+
+	for y := rect.Min.Y; y < rect.Max.Y; y += 2 {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			top := remapped.At(x, y).(color.RGBA)
+
+			// The last row of an image with an odd height has no bottom pixel
+			if y+1 < rect.Max.Y {
+				bottom := remapped.At(x, y+1).(color.RGBA)
+				fmt.Fprintf(out, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀",
+					top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			} else {
+				fmt.Fprintf(out, "\x1b[38;2;%d;%d;%dm\x1b[49m▀", top.R, top.G, top.B)
+			}
+		}
+		fmt.Fprintln(out, "\x1b[0m")
+	}
+
+	if err := out.Flush(); err != nil {
+		die(err)
+	}
+}
+
+// remap returns the given image, with every pixel replaced by the nearest
+// color in the given palette.
+func remap(img image.Image, colors []color.RGBA) *image.Paletted {
+	rect := img.Bounds()
+
+	palette := make(color.Palette, len(colors))
+	for index, clr := range colors {
+		palette[index] = clr
+	}
+
+	paletted := image.NewPaletted(rect, palette)
+	draw.Draw(paletted, rect, img, rect.Min, draw.Src)
+
+	return paletted
+}
+
+// terminalColumns returns the width of the terminal, as reported by the
+// COLUMNS environment variable.
+func terminalColumns() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return defaultColumns
+}
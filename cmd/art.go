@@ -0,0 +1,104 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+
+	"github.com/joshdk/quantize"
+)
+
+// defaultRamp is the character ramp used by default, from darkest to lightest.
+const defaultRamp = " .:-=+*#%@"
+
+func artCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize art", flag.ContinueOnError)
+	width := flags.Int("width", terminalColumns(), "width of the art in characters (default terminal width)")
+	ramp := flags.String("ramp", defaultRamp, "characters used to draw the image, from darkest to lightest")
+	plain := flags.Bool("plain", false, "print the characters without any color")
+	fixed := flags.String("palette", "", "fixed palette to remap to instead of quantizing, as an .act, .gpl, .hex, or .pal file, or a list such as '#112233,#445566'")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("image file not specified")))
+	}
+	if *width < 1 {
+		die(usageError(fmt.Errorf("invalid width %d", *width)))
+	}
+
+	characters := []rune(*ramp)
+	if len(characters) == 0 {
+		die(usageError(errors.New("character ramp is empty")))
+	}
+
+	levels := parseLevels(args[1:])
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	colors := output{palette: fixed}.colors(img, levels)
+
+	// Terminal cells are roughly twice as tall as they are wide
+	rect := img.Bounds()
+	columns := *width
+	if rect.Dx() < columns {
+		columns = rect.Dx()
+	}
+	rows := rect.Dy() * columns / maxInt(rect.Dx(), 1) / 2
+
+	scaled := scale(img, columns, maxInt(rows, 1))
+	remapped := remap(scaled, colors)
+	out := bufio.NewWriter(os.Stdout)
+
+	for y := 0; y < scaled.Bounds().Dy(); y++ {
+		for x := 0; x < columns; x++ {
+			r, g, b, _ := scaled.At(x, y).RGBA()
+			lightness := quantize.ToLab(color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xFF}).L
+
+			// Map lightness within [0, 100] onto the ramp
+			index := int(lightness / 100 * float64(len(characters)))
+			if index >= len(characters) {
+				index = len(characters) - 1
+			}
+			if index < 0 {
+				index = 0
+			}
+
+			if *plain {
+				fmt.Fprintf(out, "%c", characters[index])
+			} else {
+				clr := remapped.At(x, y).(color.RGBA)
+				fmt.Fprintf(out, "\x1b[38;2;%d;%d;%dm%c", clr.R, clr.G, clr.B, characters[index])
+			}
+		}
+
+		if *plain {
+			fmt.Fprintln(out)
+		} else {
+			fmt.Fprintln(out, "\x1b[0m")
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		die(err)
+	}
+
+}
+
+func maxInt(first int, second int) int {
+	if first > second {
+		return first
+	}
+	return second
+}
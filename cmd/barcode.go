@@ -0,0 +1,53 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"image"
+	"sort"
+
+	"github.com/joshdk/quantize"
+)
+
+// barcode renders a movie barcode, with one vertical strip of the given width
+// per frame. Each strip is divided between the palette colors of its frame,
+// from top to bottom in order of decreasing proportion.
+func barcode(frames []quantize.Result, width int, height int) *image.RGBA {
+
+	img := image.NewRGBA(image.Rect(0, 0, len(frames)*width, height))
+
+	for index, result := range frames {
+		swatches := append([]quantize.Swatch{}, result.Colors...)
+		sort.SliceStable(swatches, func(i int, j int) bool {
+			return swatches[i].Proportion > swatches[j].Proportion
+		})
+
+		var (
+			top        int
+			cumulative float64
+		)
+
+		for number, swatch := range swatches {
+			cumulative += swatch.Proportion
+
+			// The last color fills the rest of the strip, absorbing any
+			// rounding error
+			bottom := int(cumulative*float64(height) + 0.5)
+			if number == len(swatches)-1 {
+				bottom = height
+			}
+
+			for y := top; y < bottom; y++ {
+				for x := index * width; x < (index+1)*width; x++ {
+					img.SetRGBA(x, y, swatch.RGBA)
+				}
+			}
+
+			top = bottom
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+// renderBase16 prints the given palette as a base16 scheme in YAML. The
+// darkest and lightest colors become base00 and base07, with base01 through
+// base06 blended evenly between them so that luminance increases from slot to
+// slot. The most saturated remaining colors, ordered by hue, fill the accent
+// slots base08 through base0F.
+func renderBase16(colors []color.RGBA) {
+	if len(colors) == 0 {
+		die(fmt.Errorf("base16 scheme requires at least one color"))
+	}
+
+	sorted := make([]color.RGBA, len(colors))
+	copy(sorted, colors)
+
+	sort.SliceStable(sorted, func(i int, j int) bool {
+		return quantize.Luminance(sorted[i]) < quantize.Luminance(sorted[j])
+	})
+
+	darkest, lightest := sorted[0], sorted[len(sorted)-1]
+
+	var slots [16]color.RGBA
+	for index := 0; index < 8; index++ {
+		slots[index] = quantize.Mix(darkest, lightest, float64(index)/7)
+	}
+
+	// Favor the most colorful of the remaining colors
+	accents := sorted
+	if len(accents) > 2 {
+		accents = append([]color.RGBA{}, sorted[1:len(sorted)-1]...)
+	}
+	sort.SliceStable(accents, func(i int, j int) bool {
+		return quantize.ToHSL(accents[i]).S > quantize.ToHSL(accents[j]).S
+	})
+	if len(accents) > 8 {
+		accents = accents[:8]
+	}
+	sort.SliceStable(accents, func(i int, j int) bool {
+		return quantize.ToHSL(accents[i]).H < quantize.ToHSL(accents[j]).H
+	})
+
+	for index := 0; index < 8; index++ {
+		slots[index+8] = accents[index%len(accents)]
+	}
+
+	fmt.Println(`scheme: "quantize"`)
+	fmt.Println(`author: "quantize"`)
+	for index, clr := range slots {
+		fmt.Printf("base%02X: \"%s\"\n", index, strings.ToLower(quantize.Hex(clr)[1:]))
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// clipboardScript is a PowerShell script that writes the image currently on
+// the Windows clipboard to stdout as a PNG.
+const clipboardScript = `Add-Type -AssemblyName System.Windows.Forms
+$img = [Windows.Forms.Clipboard]::GetImage()
+if ($img -eq $null) { [Console]::Error.WriteLine("clipboard does not contain an image"); exit 1 }
+$buf = New-Object IO.MemoryStream
+$img.Save($buf, [Drawing.Imaging.ImageFormat]::Png)
+$out = [Console]::OpenStandardOutput()
+$out.Write($buf.ToArray(), 0, $buf.Length)`
+
+// clipboard returns the command that writes the image currently on the system
+// clipboard to stdout as a PNG.
+func clipboard() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("pngpaste", "-"), nil
+
+	case "windows":
+		return exec.Command("powershell", "-NoProfile", "-Command", clipboardScript), nil
+
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			return exec.Command("wl-paste", "--type", "image/png"), nil
+		}
+		return exec.Command("xclip", "-selection", "clipboard", "-target", "image/png", "-out"), nil
+
+	default:
+		return nil, fmt.Errorf("clipboard is not supported on %s", runtime.GOOS)
+	}
+}
+
+// loadClipboard decodes the image currently on the system clipboard.
+func loadClipboard() (image.Image, error) {
+	cmd, err := clipboard()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCommand(cmd)
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// renderGo prints the given palette as a Go variable declaration, ready to be
+// pasted into a source file that imports image/color.
+func renderGo(colors []color.RGBA) {
+	fmt.Println("var Palette = []color.RGBA{")
+	for _, clr := range colors {
+		fmt.Printf("\t{0x%02X, 0x%02X, 0x%02X, 0x%02X},\n", clr.R, clr.G, clr.B, clr.A)
+	}
+	fmt.Println("}")
+}
+
+// renderC prints the given palette as a C array of RGB triplets, ready to be
+// pasted into a header file.
+func renderC(colors []color.RGBA) {
+	fmt.Println("#include <stdint.h>")
+	fmt.Println()
+	fmt.Printf("static const uint8_t palette[%d][3] = {\n", len(colors))
+	for _, clr := range colors {
+		fmt.Printf("\t{0x%02X, 0x%02X, 0x%02X},\n", clr.R, clr.G, clr.B)
+	}
+	fmt.Println("};")
+}
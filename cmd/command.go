@@ -0,0 +1,46 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os/exec"
+	"strings"
+)
+
+// decodeCommand runs the given command, and decodes the PNG image that it
+// writes to stdout.
+func decodeCommand(cmd *exec.Cmd) (image.Image, error) {
+	output, err := runCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(output))
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	return img, nil
+}
+
+// runCommand runs the given command, and returns what it writes to stdout.
+func runCommand(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return nil, fmt.Errorf("%s is required but could not be found", cmd.Args[0])
+		}
+		return nil, fmt.Errorf("%s: %s", cmd.Args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
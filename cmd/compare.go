@@ -0,0 +1,150 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"image/color"
+	"os"
+	"sort"
+
+	"github.com/joshdk/quantize"
+)
+
+// comparison holds the palette of every compared image, and the distance
+// between every pair of them.
+type comparison struct {
+	paths    []string
+	palettes [][]color.RGBA
+	pairs    []pair
+}
+
+// pair is the palette distance between two compared images, identified by
+// their indices.
+type pair struct {
+	first    int
+	second   int
+	distance float64
+}
+
+// compareFormats maps the name of every format that a comparison may be
+// rendered in to the function that renders it.
+var compareFormats = map[string]func(comparison){
+	"terminal": renderComparison,
+	"html":     renderComparisonHTML,
+}
+
+func compareCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize compare", flag.ContinueOnError)
+	levels := flags.Int("levels", 2, "number of levels of each palette to compare")
+	format := flags.String("format", "terminal", "output format, one of terminal or html")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 2 {
+		die(usageError(errors.New("at least two image files must be specified")))
+	}
+
+	render, found := compareFormats[*format]
+	if !found {
+		die(usageError(fmt.Errorf("unknown format %q", *format)))
+	}
+
+	result := comparison{paths: args}
+	for _, path := range args {
+		img, err := load(path)
+		if err != nil {
+			die(pathError(path, err))
+		}
+		result.palettes = append(result.palettes, quantize.Image(img, *levels))
+	}
+
+	for i := range result.palettes {
+		for j := i + 1; j < len(result.palettes); j++ {
+			distance := quantize.PaletteDistance(result.palettes[i], result.palettes[j])
+			result.pairs = append(result.pairs, pair{i, j, distance})
+		}
+	}
+
+	// The most similar pairs come first
+	sort.SliceStable(result.pairs, func(i int, j int) bool {
+		return result.pairs[i].distance < result.pairs[j].distance
+	})
+
+	render(result)
+
+}
+
+// renderComparison prints every palette as a row of swatches colored with
+// 24-bit ANSI escape codes, followed by the distance between every pair of
+// images.
+func renderComparison(result comparison) {
+	out := bufio.NewWriter(os.Stdout)
+
+	var width int
+	for _, path := range result.paths {
+		if len(path) > width {
+			width = len(path)
+		}
+	}
+
+	for index, path := range result.paths {
+		fmt.Fprintf(out, "%-*s ", width, path)
+		for _, clr := range result.palettes[index] {
+			fmt.Fprintf(out, " \x1b[48;2;%d;%d;%dm    \x1b[0m", clr.R, clr.G, clr.B)
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintln(out)
+
+	for _, pair := range result.pairs {
+		fmt.Fprintf(out, "%-*s  %-*s  %.2f\n",
+			width, result.paths[pair.first], width, result.paths[pair.second], pair.distance)
+	}
+
+	if err := out.Flush(); err != nil {
+		die(err)
+	}
+}
+
+// renderComparisonHTML prints a standalone HTML page showing every image next
+// to its palette, followed by a table of the distance between every pair of
+// images.
+func renderComparisonHTML(result comparison) {
+	out := bufio.NewWriter(os.Stdout)
+
+	fmt.Fprintln(out, "<!DOCTYPE html>")
+	fmt.Fprintln(out, "<html>\n<head>\n<meta charset=\"utf-8\">\n<title>quantize compare</title>")
+	fmt.Fprintln(out, "<style>figure{display:inline-block;margin:8px}img{display:block;width:160px}.swatch{display:inline-block;width:40px;height:40px}</style>")
+	fmt.Fprintln(out, "</head>\n<body>")
+
+	for index, path := range result.paths {
+		escaped := html.EscapeString(path)
+
+		fmt.Fprintf(out, "<figure>\n<img src=\"%s\" alt=\"%s\">\n<div>", escaped, escaped)
+		for _, clr := range result.palettes[index] {
+			hex := quantize.Hex(clr)
+			fmt.Fprintf(out, "<span class=\"swatch\" style=\"background:%s\" title=\"%s\"></span>", hex, hex)
+		}
+		fmt.Fprintf(out, "</div>\n<figcaption>%s</figcaption>\n</figure>\n", escaped)
+	}
+
+	fmt.Fprintln(out, "<table>\n<tr><th>first</th><th>second</th><th>distance</th></tr>")
+	for _, pair := range result.pairs {
+		fmt.Fprintf(out, "<tr><td>%s</td><td>%s</td><td>%.2f</td></tr>\n",
+			html.EscapeString(result.paths[pair.first]), html.EscapeString(result.paths[pair.second]), pair.distance)
+	}
+	fmt.Fprintln(out, "</table>\n</body>\n</html>")
+
+	if err := out.Flush(); err != nil {
+		die(err)
+	}
+}
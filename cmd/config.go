@@ -0,0 +1,179 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// autoLevels stands in for a number of levels chosen to suit each image.
+const autoLevels = -1
+
+// defaultLevels is the number of levels used when none are given, which may
+// be overridden by the config file. By default, the levels are chosen to suit
+// each image.
+var defaultLevels = autoLevels
+
+// parse registers the flags shared by every command, parses the given
+// arguments, and then applies the values from the config file as defaults for
+// every flag that was not set explicitly.
+func parse(flags *flag.FlagSet, args []string) {
+	path := flags.String("config", "", "path to a config file of default settings (default ~/.config/quantize/config.yaml)")
+	flags.BoolVar(&quiet, "quiet", false, "suppress error messages, leaving only the exit code")
+	flags.BoolVar(&jsonErrors, "json-errors", false, "report errors as JSON objects on stderr")
+
+	// The flag package prints its own errors and usage, which would defeat
+	// quiet and machine readable errors, so those are left to die instead
+	if requested(args, "quiet") || requested(args, "json-errors") {
+		flags.SetOutput(ioutil.Discard)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		die(usageError(err))
+	}
+
+	if err := configure(flags, *path); err != nil {
+		die(usageError(err))
+	}
+}
+
+// requested reports whether the given boolean flag is set among the given
+// arguments, before they are parsed.
+func requested(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		parts := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+		if parts[0] != name {
+			continue
+		}
+		if len(parts) == 1 {
+			return true
+		}
+		if value, err := strconv.ParseBool(parts[1]); err == nil {
+			return value
+		}
+	}
+
+	return false
+}
+
+// configure loads the config file at the given path, or the default config
+// file if the path is empty. Every key in the file names a flag, except for
+// "levels" which sets the default number of levels, or "auto". Keys that are
+// not understood by the current command are ignored.
+func configure(flags *flag.FlagSet, path string) error {
+
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	settings, err := readConfig(path)
+	if err != nil {
+		// A missing default config file is not an error
+		if os.IsNotExist(err) && !explicit {
+			return nil
+		}
+		return err
+	}
+
+	// Flags given on the command line take precedence over the config file
+	given := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	for key, value := range settings {
+		switch {
+		case key == "levels" && value == "auto":
+			defaultLevels = autoLevels
+
+		case key == "levels":
+			levels, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s: invalid levels %q", path, value)
+			}
+			defaultLevels = levels
+
+		case flags.Lookup(key) != nil && !given[key]:
+			if err := flags.Set(key, value); err != nil {
+				return fmt.Errorf("%s: invalid %s %q", path, key, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// defaultConfigPath returns the location of the default config file.
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+
+	return filepath.Join(dir, "quantize", "config.yaml")
+}
+
+// readConfig reads a config file consisting of flat "key: value" YAML pairs.
+// Values may be quoted, and lines starting with a "#" are comments.
+func readConfig(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	settings := map[string]string{}
+	scanner := bufio.NewScanner(file)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("%s:%d: expected a key: value pair", path, line)
+		}
+
+		value, err := unquote(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, line, err.Error())
+		}
+
+		settings[strings.TrimSpace(parts[0])] = value
+	}
+
+	return settings, scanner.Err()
+}
+
+// unquote removes YAML style single or double quotes from the given value.
+func unquote(value string) (string, error) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		return strconv.Unquote(value)
+
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joshdk/quantize"
+	"github.com/joshdk/quantize/apng"
+)
+
+func cycleCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize cycle", flag.ContinueOnError)
+	delay := flags.Int("delay", 10, "delay between frames, in hundredths of a second")
+	start := flags.Int("start", 0, "index of the first palette color to cycle")
+	end := flags.Int("end", -1, "index after the last palette color to cycle (default the palette length)")
+	fixed := flags.String("palette", "", "fixed palette to remap to instead of quantizing, as an .act, .gpl, .hex, or .pal file, or a list such as '#112233,#445566'")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 2 {
+		die(usageError(errors.New("image file and output file not specified")))
+	}
+	if *delay < 0 {
+		die(usageError(fmt.Errorf("invalid delay %d", *delay)))
+	}
+
+	levels := parseLevels(args[2:])
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	// Adjacent colors are similar, so that the animation flows smoothly
+	colors := output{palette: fixed}.colors(img, levels)
+	if *fixed == "" {
+		colors = quantize.SimilarityOrder(colors)
+	}
+
+	if *end < 0 || *end > len(colors) {
+		*end = len(colors)
+	}
+	if *start < 0 || *start >= *end {
+		die(usageError(fmt.Errorf("invalid range of colors %d to %d", *start, *end)))
+	}
+
+	paletted := remap(img, colors)
+
+	var (
+		frames []*image.Paletted
+		delays []int
+	)
+	for _, palette := range quantize.Cycle(colors, *start, *end, *end-*start) {
+		frames = append(frames, quantize.Recolor(paletted, palette))
+		delays = append(delays, *delay)
+	}
+
+	if err := saveAnimation(args[1], frames, delays); err != nil {
+		die(err)
+	}
+
+}
+
+// saveAnimation encodes the given frames as an animated image file at the
+// given path, which is an APNG if the path ends in .png or .apng, and
+// otherwise a GIF.
+func saveAnimation(path string, frames []*image.Paletted, delays []int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".apng":
+		animation := apng.APNG{Delay: delays}
+		for _, frame := range frames {
+			animation.Image = append(animation.Image, frame)
+		}
+		err = apng.EncodeAll(file, &animation)
+
+	default:
+		err = gif.EncodeAll(file, &gif.GIF{Image: frames, Delay: delays})
+	}
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
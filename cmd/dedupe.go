@@ -0,0 +1,164 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+// imageExtensions are the file extensions of images found within directories.
+var imageExtensions = map[string]bool{
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+}
+
+// layoutSize is the number of columns and rows of tiles compared between
+// images, so that images with the same colors in different places differ.
+const layoutSize = 4
+
+// descriptor summarizes the colors of an image, and where they are.
+type descriptor struct {
+	path    string
+	palette []color.RGBA
+	layout  []color.RGBA
+}
+
+func dedupeCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize dedupe", flag.ContinueOnError)
+	threshold := flags.Float64("threshold", 5, "largest average perceptual distance between similar images")
+	levels := flags.Int("levels", 2, "number of levels of each palette to compare")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("directory not specified")))
+	}
+
+	paths, err := imageFiles(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	descriptors := make([]descriptor, 0, len(paths))
+	for _, path := range paths {
+		img, err := load(path)
+		if err != nil {
+			warn(pathError(path, err))
+			continue
+		}
+
+		var layout []color.RGBA
+		for _, tile := range quantize.Grid(img, layoutSize, layoutSize, 0) {
+			layout = append(layout, tile[0])
+		}
+
+		descriptors = append(descriptors, descriptor{path, quantize.Image(img, *levels), layout})
+	}
+
+	for index, cluster := range clusters(descriptors, *threshold) {
+		if index > 0 {
+			fmt.Println()
+		}
+		for _, path := range cluster {
+			fmt.Println(path)
+		}
+	}
+
+	if skipped := len(paths) - len(descriptors); skipped > 0 {
+		die(partialError(skipped, len(paths)))
+	}
+
+}
+
+// imageFiles returns the paths of every image within the given directory and
+// its subdirectories, in lexical order.
+func imageFiles(dir string) ([]string, error) {
+	var paths []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && imageExtensions[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	return paths, err
+}
+
+// clusters groups the given images into clusters of similar images, where
+// every image is similar to at least one other image in its cluster. Images
+// similar to no other image are omitted.
+func clusters(descriptors []descriptor, threshold float64) [][]string {
+
+	// Each image starts in a cluster of its own, identified by its index
+	parents := make([]int, len(descriptors))
+	for index := range parents {
+		parents[index] = index
+	}
+
+	var root func(int) int
+	root = func(index int) int {
+		if parents[index] != index {
+			parents[index] = root(parents[index])
+		}
+		return parents[index]
+	}
+
+	for i := range descriptors {
+		for j := i + 1; j < len(descriptors); j++ {
+			if similar(descriptors[i], descriptors[j], threshold) {
+				parents[root(j)] = root(i)
+			}
+		}
+	}
+
+	members := make(map[int][]string)
+	for index, descriptor := range descriptors {
+		members[root(index)] = append(members[root(index)], descriptor.path)
+	}
+
+	var groups [][]string
+	for _, group := range members {
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	// Paths are already sorted within each cluster
+	sort.Slice(groups, func(i int, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	return groups
+}
+
+// similar reports whether two images have similar palettes, and similar
+// colors laid out in similar places.
+func similar(first descriptor, second descriptor, threshold float64) bool {
+	var layout float64
+	for index := range first.layout {
+		layout += quantize.DeltaE(first.layout[index], second.layout[index])
+	}
+	layout /= float64(len(first.layout))
+
+	palette := quantize.PaletteDistance(first.palette, second.palette)
+
+	return layout <= threshold && palette <= threshold
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/joshdk/quantize"
+)
+
+// writeDiff writes a heat map of the error between the given image and the
+// image remapped to its palette to a PNG file at the given path, if requested.
+func writeDiff(path string, img image.Image, colors []color.RGBA) {
+	if path == "" {
+		return
+	}
+
+	if err := savePNG(path, quantize.Difference(img, colors)); err != nil {
+		die(err)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// downloaders maps object storage URL schemes to the external command that
+// writes the object at such a URL to stdout. The cloud CLIs are used rather
+// than their SDKs, so that their existing credentials and configuration are
+// honored.
+var downloaders = map[string]func(url string) *exec.Cmd{
+	"s3://": func(url string) *exec.Cmd {
+		return exec.Command("aws", "s3", "cp", "--quiet", url, "-")
+	},
+	"gs://": func(url string) *exec.Cmd {
+		return exec.Command("gsutil", "cat", url)
+	},
+}
+
+// downloader returns the command used to download the object at the given
+// URL, or false if the path is not an object storage URL.
+func downloader(path string) (*exec.Cmd, bool) {
+	for scheme, command := range downloaders {
+		if strings.HasPrefix(path, scheme) {
+			return command(path), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+
+	"github.com/joshdk/quantize"
+	"github.com/joshdk/quantize/palette"
+)
+
+func duotoneCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize duotone", flag.ContinueOnError)
+	tones := flags.Int("tones", 2, "number of tones, either 2 for a duotone or 3 for a tritone")
+	fixed := flags.String("colors", "", "colors to use from shadows to highlights instead of extracting them, such as '#112233,#ffeedd'")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 2 {
+		die(usageError(errors.New("image file and output file not specified")))
+	}
+	if *tones < 2 || *tones > 3 {
+		die(usageError(fmt.Errorf("invalid number of tones %d", *tones)))
+	}
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	var stops []color.RGBA
+	if *fixed != "" {
+		if stops, err = palette.Load(*fixed); err != nil {
+			die(usageError(err))
+		}
+	} else {
+		stops = quantize.Tones(img, *tones)
+	}
+
+	if err := savePNG(args[1], quantize.Duotone(img, stops)); err != nil {
+		die(err)
+	}
+
+}
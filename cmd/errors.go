@@ -0,0 +1,109 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+)
+
+// The exit codes used to distinguish between classes of failure.
+const (
+	// exitFailure is used for any failure not covered by another class.
+	exitFailure = 1
+
+	// exitUsage is used when the given flags or arguments are invalid.
+	exitUsage = 2
+
+	// exitDecode is used when an image could not be decoded.
+	exitDecode = 3
+
+	// exitUnsupported is used when an image is in an unsupported format.
+	exitUnsupported = 4
+
+	// exitPartial is used when a batch of images was processed, but some of
+	// them were skipped.
+	exitPartial = 5
+)
+
+var (
+	// quiet suppresses all error messages, leaving only the exit code.
+	quiet bool
+
+	// jsonErrors reports errors as JSON objects on stderr.
+	jsonErrors bool
+)
+
+// exitError is an error annotated with the class of failure it represents.
+type exitError struct {
+	code  int
+	class string
+	err   error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+// usageError marks the given error as an invalid flag or argument.
+func usageError(err error) error {
+	return exitError{exitUsage, "usage", err}
+}
+
+// decodeError marks the given error as a failure to decode an image, which is
+// further classified if the image format was not recognized.
+func decodeError(err error) error {
+	if err == image.ErrFormat {
+		return exitError{exitUnsupported, "unsupported", err}
+	}
+	return exitError{exitDecode, "decode", err}
+}
+
+// partialError reports that the given number of images within a batch were
+// skipped.
+func partialError(skipped int, total int) error {
+	return exitError{exitPartial, "partial", fmt.Errorf("%d of %d images skipped", skipped, total)}
+}
+
+// pathError prefixes the given error with the path of the file that caused it,
+// keeping its class of failure.
+func pathError(path string, err error) error {
+	prefixed := fmt.Errorf("%s: %s", path, err.Error())
+	if exit, ok := err.(exitError); ok {
+		return exitError{exit.code, exit.class, prefixed}
+	}
+	return prefixed
+}
+
+func die(err error) {
+	code, class := exitFailure, "failure"
+	if exit, ok := err.(exitError); ok {
+		code, class = exit.code, exit.class
+	}
+
+	switch {
+	case quiet:
+	case jsonErrors:
+		json.NewEncoder(os.Stderr).Encode(struct {
+			Error string `json:"error"`
+			Class string `json:"class"`
+			Code  int    `json:"code"`
+		}{err.Error(), class, code})
+	default:
+		fmt.Fprintf(os.Stderr, "quantize: %s\n", err.Error())
+	}
+
+	os.Exit(code)
+}
+
+// warn reports an error that does not stop the command, such as a single
+// unreadable file within a directory of images.
+func warn(err error) {
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "quantize: %s\n", err.Error())
+	}
+}
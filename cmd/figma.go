@@ -0,0 +1,63 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"encoding/json"
+	"image/color"
+	"os"
+)
+
+// figmaColor is a color as represented by the Figma API, with components
+// within [0, 1].
+type figmaColor struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+	A float64 `json:"a"`
+}
+
+// figmaPaint is a solid fill as represented by the Figma API.
+type figmaPaint struct {
+	Type  string     `json:"type"`
+	Color figmaColor `json:"color"`
+}
+
+// figmaStyle is a named fill style as represented by the Figma API.
+type figmaStyle struct {
+	Name      string       `json:"name"`
+	StyleType string       `json:"styleType"`
+	Fills     []figmaPaint `json:"fills"`
+}
+
+// renderFigma prints the given palette as a JSON array of Figma fill styles,
+// named by their step on a lightest to darkest scale, such as "Palette/100".
+func renderFigma(colors []color.RGBA) {
+	sorted, names := steps(colors)
+
+	styles := make([]figmaStyle, len(sorted))
+	for index, clr := range sorted {
+		styles[index] = figmaStyle{
+			Name:      "Palette/" + names[index],
+			StyleType: "FILL",
+			Fills: []figmaPaint{{
+				Type: "SOLID",
+				Color: figmaColor{
+					R: float64(clr.R) / 255,
+					G: float64(clr.G) / 255,
+					B: float64(clr.B) / 255,
+					A: float64(clr.A) / 255,
+				},
+			}},
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(styles); err != nil {
+		die(err)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+)
+
+// icoHeader is the magic number that every ICO file starts with.
+const icoHeader = "\x00\x00\x01\x00"
+
+// pngHeader is the magic number that every PNG image starts with.
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// maxDIBSize is the largest width or height of a bitmap within an ICO file,
+// which the format itself cannot exceed.
+const maxDIBSize = 256
+
+func init() {
+	// Favicons are commonly ICO files, which the standard library does not
+	// support
+	image.RegisterFormat("ico", icoHeader, decodeICO, decodeICOConfig)
+}
+
+// icoEntry is an entry of the directory of images within an ICO file.
+type icoEntry struct {
+	Width, Height uint8
+	Colors        uint8
+	Reserved      uint8
+	Planes        uint16
+	BitCount      uint16
+	Size          uint32
+	Offset        uint32
+}
+
+// decodeICO decodes the largest image within an ICO file. Images may be
+// stored as PNG images, or as uncompressed 24 or 32 bit bitmaps.
+func decodeICO(r io.Reader) (image.Image, error) {
+	data, entry, err := readICO(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body := data[entry.Offset : entry.Offset+entry.Size]
+	if bytes.HasPrefix(body, []byte(pngHeader)) {
+		return png.Decode(bytes.NewReader(body))
+	}
+
+	return decodeDIB(body)
+}
+
+// decodeICOConfig returns the dimensions of the largest image within an ICO
+// file, without decoding its pixels, so that decode limits can be checked
+// first.
+func decodeICOConfig(r io.Reader) (image.Config, error) {
+	data, entry, err := readICO(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	body := data[entry.Offset : entry.Offset+entry.Size]
+	if bytes.HasPrefix(body, []byte(pngHeader)) {
+		return png.DecodeConfig(bytes.NewReader(body))
+	}
+
+	header, err := readDIBHeader(body)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	return image.Config{
+		ColorModel: color.NRGBAModel,
+		Width:      header.width,
+		Height:     header.height,
+	}, nil
+}
+
+// readICO reads an entire ICO file, and returns its directory entry for the
+// largest image.
+func readICO(r io.Reader) ([]byte, icoEntry, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, icoEntry{}, err
+	}
+
+	if len(data) < 6 || string(data[:4]) != icoHeader {
+		return nil, icoEntry{}, image.ErrFormat
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	entries := make([]icoEntry, count)
+	if err := binary.Read(bytes.NewReader(data[6:]), binary.LittleEndian, entries); err != nil {
+		return nil, icoEntry{}, errors.New("ico: invalid directory")
+	}
+
+	// A width or height of zero stands for 256 pixels
+	size := func(entry icoEntry) int {
+		width, height := int(entry.Width), int(entry.Height)
+		if width == 0 {
+			width = 256
+		}
+		if height == 0 {
+			height = 256
+		}
+		return width * height
+	}
+
+	best := -1
+	for index, entry := range entries {
+		if uint64(entry.Offset)+uint64(entry.Size) > uint64(len(data)) {
+			continue
+		}
+		if best < 0 || size(entry) > size(entries[best]) {
+			best = index
+		}
+	}
+
+	if best < 0 {
+		return nil, icoEntry{}, errors.New("ico: no valid images")
+	}
+
+	return data, entries[best], nil
+}
+
+// decodeDIB decodes an uncompressed 24 or 32 bit bitmap, as stored within an
+// ICO file. Such bitmaps are stored bottom up, and their height is doubled to
+// account for a transparency mask, which is only applied to 24 bit bitmaps
+// since 32 bit bitmaps have their own alpha channel.
+func decodeDIB(data []byte) (image.Image, error) {
+	header, err := readDIBHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height, depth := header.width, header.height, header.depth
+	stride := (width*depth + 3) &^ 3
+	maskStride := ((width+7)/8 + 3) &^ 3
+
+	pixels := data[header.size:]
+	if len(pixels) < stride*height {
+		return nil, errors.New("ico: truncated bitmap")
+	}
+
+	// The mask is only consulted when it is present in full
+	mask := pixels[stride*height:]
+	if len(mask) < maskStride*height {
+		mask = nil
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		row := pixels[(height-1-y)*stride:]
+
+		for x := 0; x < width; x++ {
+			pixel := row[x*depth:]
+			clr := color.NRGBA{pixel[2], pixel[1], pixel[0], 0xFF}
+
+			switch {
+			case depth == 4:
+				clr.A = pixel[3]
+
+			case mask != nil:
+				if mask[(height-1-y)*maskStride+x/8]&(0x80>>uint(x%8)) != 0 {
+					clr.A = 0
+				}
+			}
+
+			img.SetNRGBA(x, y, clr)
+		}
+	}
+
+	return img, nil
+}
+
+// dibHeader is the validated header of a bitmap within an ICO file.
+type dibHeader struct {
+	// size is the length of the header, after which the pixels follow.
+	size int
+
+	// width and height are the dimensions of the image, excluding the
+	// transparency mask.
+	width, height int
+
+	// depth is the number of bytes per pixel.
+	depth int
+}
+
+// readDIBHeader reads and validates the header of an uncompressed 24 or 32 bit
+// bitmap, as stored within an ICO file.
+func readDIBHeader(data []byte) (dibHeader, error) {
+	var header struct {
+		Size        uint32
+		Width       int32
+		Height      int32
+		Planes      uint16
+		BitCount    uint16
+		Compression uint32
+	}
+
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return dibHeader{}, errors.New("ico: invalid bitmap")
+	}
+	if header.Compression != 0 || (header.BitCount != 24 && header.BitCount != 32) {
+		return dibHeader{}, errors.New("ico: unsupported bitmap")
+	}
+
+	if header.Size < uint32(binary.Size(header)) || uint64(header.Size) > uint64(len(data)) {
+		return dibHeader{}, errors.New("ico: invalid bitmap")
+	}
+
+	width, height := int(header.Width), int(header.Height)/2
+	if width <= 0 || height <= 0 {
+		return dibHeader{}, errors.New("ico: invalid bitmap")
+	}
+	if width > maxDIBSize || height > maxDIBSize {
+		return dibHeader{}, errors.New("ico: bitmap too large")
+	}
+
+	return dibHeader{int(header.Size), width, height, int(header.BitCount) / 8}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+func liveCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize live", flag.ContinueOnError)
+	out := outputFlags(flags)
+	rate := flags.Duration("rate", time.Second, "interval between palette updates")
+	lights := ambilightFlags(flags)
+	broker := mqttFlags(flags)
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("MJPEG stream URL or camera device not specified")))
+	}
+	if *rate <= 0 {
+		die(usageError(fmt.Errorf("invalid rate %s", *rate)))
+	}
+
+	renderer := out.renderer()
+	levels := parseLevels(args[1:])
+
+	sender := lights.dial()
+	update := func(_ time.Duration, img image.Image) {
+		if sender != nil {
+			lights.send(sender, img)
+			return
+		}
+		colors := out.colors(img, levels)
+		renderer(img, colors)
+		broker.publish(img, colors)
+	}
+
+	var err error
+	if strings.HasPrefix(args[0], "http://") || strings.HasPrefix(args[0], "https://") {
+		err = mjpegFrames(args[0], *rate, update)
+	} else {
+		err = cameraFrames(args[0], *rate, update)
+	}
+	if err != nil {
+		die(err)
+	}
+
+}
+
+// mjpegFrames reads the MJPEG stream served at the given URL, and calls the
+// given function with the latest frame and its timestamp at most once every
+// interval. Frames that arrive in between are decoded but otherwise dropped.
+func mjpegFrames(url string, every time.Duration, fn func(time.Duration, image.Image)) error {
+
+	response, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, response.Status)
+	}
+
+	mediatype, params, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediatype, "multipart/") || params["boundary"] == "" {
+		return fmt.Errorf("%s: not an MJPEG stream", url)
+	}
+
+	// Some cameras include the leading dashes in the boundary parameter
+	reader := multipart.NewReader(response.Body, strings.TrimPrefix(params["boundary"], "--"))
+
+	var start, last time.Time
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		img, err := jpeg.Decode(part)
+		if err != nil {
+			return decodeError(err)
+		}
+
+		now := time.Now()
+		if start.IsZero() {
+			start = now
+		} else if now.Sub(last) < every {
+			continue
+		}
+
+		last = now
+		fn(now.Sub(start), img)
+	}
+}
+
+// cameraFrames captures a frame from the given camera device at every
+// interval using ffmpeg, and calls the given function with each frame and its
+// timestamp.
+func cameraFrames(device string, every time.Duration, fn func(time.Duration, image.Image)) error {
+	var format string
+
+	switch runtime.GOOS {
+	case "linux":
+		format = "v4l2"
+
+	case "darwin":
+		format = "avfoundation"
+
+	case "windows":
+		format = "dshow"
+
+	default:
+		return fmt.Errorf("cameras are not supported on %s", runtime.GOOS)
+	}
+
+	return ffmpegFrames([]string{"-f", format, "-i", device}, every, fn)
+}
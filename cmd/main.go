@@ -5,60 +5,387 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
 	_ "image/gif"
 	_ "image/jpeg"
-	_ "image/png"
+	"image/png"
 	"os"
 	"strconv"
 
 	"github.com/joshdk/quantize"
+	"github.com/joshdk/quantize/palette"
 )
 
-func die(err error) {
-	fmt.Printf("quantize: %s\n", err.Error())
-	os.Exit(1)
+// commands maps the name of every subcommand to its entrypoint. Without a
+// subcommand, the palette of the given image file is printed.
+var commands = map[string]func(args []string){
+	"album":   albumCommand,
+	"art":     artCommand,
+	"compare": compareCommand,
+	"cycle":   cycleCommand,
+	"duotone": duotoneCommand,
+	"dedupe":  dedupeCommand,
+	"live":    liveCommand,
+	"screen":  screenCommand,
+	"search":  searchCommand,
+	"site":    siteCommand,
+	"theme":   themeCommand,
+	"vector":  vectorCommand,
+	"video":   videoCommand,
 }
 
+// formats maps the name of every supported output format to the function
+// that renders a palette in that format.
+var formats = map[string]func([]color.RGBA){
+	"hex":      renderHex,
+	"css":      renderCSS,
+	"go":       renderGo,
+	"c":        renderC,
+	"tokens":   renderTokens,
+	"figma":    renderFigma,
+	"lospec":   renderLospec,
+	"jasc":     renderJASC,
+	"aseprite": renderAseprite,
+	"png":      renderPNG,
+	"gpl":      renderGPL,
+	"base16":   renderBase16,
+	"svg":      renderSVG,
+}
+
+// imageFormats maps the name of every output format that needs the image as
+// well as its palette to the function that renders it. The ansi format prints
+// the image remapped to its palette, the json and markdown formats measure the
+// population of every palette color, and the histogram format ignores the
+// palette entirely.
+var imageFormats = map[string]func(image.Image, []color.RGBA){
+	"ansi":      renderANSI,
+	"json":      renderJSON,
+	"histogram": renderHistogram,
+	"markdown":  renderMarkdown,
+}
+
+// histogramBins is the number of bins per channel printed by the histogram
+// format.
+const histogramBins = 16
+
 func render(clr color.RGBA) {
-	fmt.Printf("#%02X%02X%02X\n", clr.R, clr.G, clr.B)
+	fmt.Println(quantize.Hex(clr))
 }
 
-func main() {
+func renderHex(colors []color.RGBA) {
+	for _, clr := range colors {
+		render(clr)
+	}
+}
+
+func renderCSS(colors []color.RGBA) {
+	fmt.Println(quantize.CSSGradient(colors))
+}
+
+func renderJSON(img image.Image, colors []color.RGBA) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
 
-	if len(os.Args) < 2 {
-		die(errors.New("image file not specified"))
+	if err := encoder.Encode(quantize.Measure(img, colors)); err != nil {
+		die(err)
 	}
+}
 
-	path := os.Args[1]
+// renderHistogram prints the RGB and HSV histograms of the given image as
+// JSON. The palette is not needed.
+func renderHistogram(img image.Image, _ []color.RGBA) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
 
-	levels := 4
+	if err := encoder.Encode(quantize.Histogram(img, histogramBins)); err != nil {
+		die(err)
+	}
+}
+
+func renderLospec(colors []color.RGBA) {
+	if err := palette.EncodeLospec(os.Stdout, colors); err != nil {
+		die(err)
+	}
+}
+
+func renderJASC(colors []color.RGBA) {
+	if err := palette.EncodeJASC(os.Stdout, colors); err != nil {
+		die(err)
+	}
+}
+
+func renderAseprite(colors []color.RGBA) {
+	if err := palette.EncodeAseprite(os.Stdout, colors); err != nil {
+		die(err)
+	}
+}
 
-	if len(os.Args) >= 3 {
-		var err error
-		levels, err = strconv.Atoi(os.Args[2])
+func renderPNG(colors []color.RGBA) {
+	if err := palette.EncodePNG(os.Stdout, colors); err != nil {
+		die(err)
+	}
+}
+
+func renderGPL(colors []color.RGBA) {
+	if err := palette.EncodeGPL(os.Stdout, colors); err != nil {
+		die(err)
+	}
+}
+
+// output holds the flags shared by every command that prints a palette.
+type output struct {
+	format   *string
+	template *string
+	preview  *bool
+	palette  *string
+	auto     *bool
+	snap     *string
+}
+
+// outputFlags registers the flags shared by every command that prints a
+// palette with the given flag set.
+func outputFlags(flags *flag.FlagSet) output {
+	return output{
+		format:   flags.String("format", "hex", "output format, one of hex, css, go, c, tokens, figma, lospec, jasc, aseprite, png, gpl, base16, svg, json, markdown, histogram, or ansi"),
+		template: flags.String("template", "", "Go template rendered once per color, such as '{{.Hex}} {{.Population}}'"),
+		preview:  flags.Bool("show", false, "display the image and its palette inline in the terminal"),
+		palette:  flags.String("palette", "", "fixed palette to remap to instead of quantizing, as an .act, .gpl, .hex, or .pal file, or a list such as '#112233,#445566'"),
+		auto:     flags.Bool("auto", false, "keep the exact colors of screenshots and illustrations, and only quantize photos"),
+		snap:     flags.String("snap", "", "hardware format to snap the palette to, one of rgb565, rgb555, or rgb444"),
+	}
+}
+
+// hardwareFormats maps the name of every hardware format to snap to.
+var hardwareFormats = map[string]quantize.Format{
+	"rgb565": quantize.RGB565,
+	"rgb555": quantize.RGB555,
+	"rgb444": quantize.RGB444,
+}
+
+// colors returns the fixed palette if one was given, and otherwise performs
+// MMCQ on the given image to the given number of levels, unless the image
+// should keep its exact colors and that was requested. Automatic levels are
+// chosen to suit the image. The palette is then snapped to a hardware format,
+// if one was given.
+func (o output) colors(img image.Image, levels int) []color.RGBA {
+	colors := o.unsnapped(img, levels)
+
+	if o.snap == nil || *o.snap == "" {
+		return colors
+	}
+
+	format, found := hardwareFormats[*o.snap]
+	if !found {
+		die(usageError(fmt.Errorf("unknown hardware format %q", *o.snap)))
+	}
+
+	return quantize.Snap(colors, format)
+}
+
+// unsnapped returns the fixed palette if one was given, and otherwise performs
+// MMCQ on the given image to the given number of levels.
+func (o output) unsnapped(img image.Image, levels int) []color.RGBA {
+	if levels == autoLevels {
+		levels = quantize.LevelsAuto(img)
+	}
+
+	if *o.palette == "" {
+		if o.auto != nil && *o.auto {
+			return quantize.Auto(img, levels)
+		}
+		return quantize.Image(img, levels)
+	}
+
+	colors, err := palette.Load(*o.palette)
+	if err != nil {
+		die(usageError(err))
+	}
+	if len(colors) == 0 {
+		die(usageError(fmt.Errorf("%s: palette is empty", *o.palette)))
+	}
+
+	return colors
+}
+
+// renderer returns the function that renders an image and its palette in the
+// chosen format, or with the given template if there is one.
+func (o output) renderer() func(image.Image, []color.RGBA) {
+	if *o.template != "" {
+		renderer, err := templateRenderer(*o.template)
 		if err != nil {
-			die(err)
+			die(usageError(err))
 		}
+		return renderer
+	}
+
+	if renderer, found := imageFormats[*o.format]; found {
+		return renderer
+	}
+
+	renderer, found := formats[*o.format]
+	if !found {
+		die(usageError(fmt.Errorf("unknown format %q", *o.format)))
+	}
+
+	return ignoreImage(renderer)
+}
+
+// ignoreImage adapts a function that renders only a palette.
+func ignoreImage(renderer func([]color.RGBA)) func(image.Image, []color.RGBA) {
+	return func(_ image.Image, colors []color.RGBA) {
+		renderer(colors)
+	}
+}
+
+// show displays the given image and its palette inline in the terminal, if
+// requested.
+func (o output) show(img image.Image, colors []color.RGBA) {
+	if !*o.preview {
+		return
+	}
+
+	if err := preview(img, colors); err != nil {
+		die(err)
+	}
+}
+
+// parseLevels parses the optional levels argument, which defaults to levels
+// chosen to suit each image unless overridden by the config file.
+func parseLevels(args []string) int {
+	if len(args) < 1 {
+		return defaultLevels
+	}
+
+	if args[0] == "auto" {
+		return autoLevels
+	}
+
+	levels, err := strconv.Atoi(args[0])
+	if err != nil {
+		die(usageError(err))
+	}
+
+	return levels
+}
+
+// load decodes the image at the given path. Vector formats such as PDF & SVG
+// are rasterized first, and objects in S3 or GCS are downloaded first, using
+// an external tool.
+func load(path string) (image.Image, error) {
+
+	if cmd, found := downloader(path); found {
+		data, err := runCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, decodeError(err)
+		}
+
+		return img, nil
+	}
+
+	if cmd, found := rasterizer(path); found {
+		return decodeCommand(cmd)
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		die(err)
+		return nil, err
 	}
+	defer file.Close()
 
 	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	return img, nil
+}
+
+// savePNG encodes the given image as a PNG file at the given path.
+func savePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func paletteCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize", flag.ContinueOnError)
+	out := outputFlags(flags)
+	paste := flags.Bool("clipboard", false, "read the image from the system clipboard instead of a file")
+	lqip := placeholderFlags(flags)
+	masks := flags.String("masks", "", "also write a binary mask of the pixels of every palette color, to PNG files named PREFIX-INDEX.png")
+	diff := flags.String("diff", "", "also write a heat map of the error between the image and the image remapped to its palette, to a PNG file")
+	remapped := flags.String("remap", "", "also write the image remapped to its palette, or to the -palette given, to a PNG file")
+	dithered := flags.Bool("dither", true, "dither the image written by -remap with Floyd-Steinberg error diffusion")
+	broker := mqttFlags(flags)
+	parse(flags, args)
+
+	renderer := out.renderer()
+	args = flags.Args()
+
+	// The image file is only needed when not reading from the clipboard
+	var path string
+	if !*paste {
+		if len(args) < 1 {
+			die(usageError(errors.New("image file not specified")))
+		}
+		path, args = args[0], args[1:]
+	}
+
+	levels := parseLevels(args)
+
+	var img image.Image
+	var err error
+
+	if *paste {
+		img, err = loadClipboard()
+	} else {
+		img, err = load(path)
+	}
 	if err != nil {
 		die(err)
 	}
 
-	colors := quantize.Image(img, levels)
+	colors := out.colors(img, levels)
 
-	for _, clr := range colors {
-		render(clr)
+	renderer(img, colors)
+	out.show(img, colors)
+	lqip.write(img)
+	writeMasks(*masks, img, colors)
+	writeDiff(*diff, img, colors)
+	writeRemapped(*remapped, img, colors, *dithered)
+	broker.publish(img, colors)
+
+}
+
+func main() {
+
+	if len(os.Args) >= 2 {
+		if command, found := commands[os.Args[1]]; found {
+			command(os.Args[2:])
+			return
+		}
 	}
 
+	paletteCommand(os.Args[1:])
+
 }
@@ -0,0 +1,43 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"net/url"
+
+	"github.com/joshdk/quantize"
+)
+
+// renderMarkdown prints the given palette as a Markdown table, with a swatch,
+// the hex, RGB, & HSL values, and the population of every color within the
+// given image.
+func renderMarkdown(img image.Image, colors []color.RGBA) {
+	result := quantize.Measure(img, colors)
+
+	fmt.Println("| Swatch | Hex | RGB | HSL | Population |")
+	fmt.Println("| :----: | --- | --- | --- | ---------: |")
+
+	for _, swatch := range result.Colors {
+		fmt.Printf("| %s | `%s` | `rgb(%d, %d, %d)` | `hsl(%.0f, %.0f%%, %.0f%%)` | %d (%.1f%%) |\n",
+			markdownSwatch(swatch.RGBA),
+			swatch.Hex,
+			swatch.RGBA.R, swatch.RGBA.G, swatch.RGBA.B,
+			swatch.HSL.H, swatch.HSL.S*100, swatch.HSL.L*100,
+			swatch.Population, swatch.Proportion*100,
+		)
+	}
+}
+
+// markdownSwatch returns a Markdown image of a small square of the given
+// color, embedded as an SVG data URI so that no external files are needed.
+func markdownSwatch(clr color.RGBA) string {
+	hex := quantize.Hex(clr)
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20"><rect width="20" height="20" fill="%s"/></svg>`, hex)
+
+	return fmt.Sprintf("![%s](data:image/svg+xml,%s)", hex, url.PathEscape(svg))
+}
@@ -0,0 +1,28 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/joshdk/quantize"
+)
+
+// writeMasks writes one binary mask per palette color of the given image to a
+// PNG file named by the given prefix and the index of the color, such as
+// "mask-0.png", if requested.
+func writeMasks(prefix string, img image.Image, colors []color.RGBA) {
+	if prefix == "" {
+		return
+	}
+
+	for index, mask := range quantize.Masks(img, colors) {
+		if err := savePNG(fmt.Sprintf("%s-%d.png", prefix, index), mask); err != nil {
+			die(err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/color"
+	"net"
+	"os/exec"
+
+	"github.com/joshdk/quantize"
+)
+
+// mqttPayload is the message published for every palette. The dominant color
+// is given separately, for home automation rules that set a single light.
+type mqttPayload struct {
+	Dominant string   `json:"dominant"`
+	RGB      [3]uint8 `json:"rgb"`
+	quantize.Result
+}
+
+// mqttOutput holds the flags for publishing palettes to an MQTT broker.
+type mqttOutput struct {
+	broker *string
+	topic  *string
+}
+
+// mqttFlags registers the flags for publishing palettes to an MQTT broker with
+// the given flag set.
+func mqttFlags(flags *flag.FlagSet) mqttOutput {
+	return mqttOutput{
+		broker: flags.String("mqtt", "", "also publish the palette as JSON to the MQTT broker at the given host[:port]"),
+		topic:  flags.String("topic", "quantize/palette", "MQTT topic that palettes are published to"),
+	}
+}
+
+// publish publishes the palette of the given image as a retained message, so
+// that subscribers see the latest palette as soon as they connect, if
+// requested. The mosquitto_pub client is used rather than an MQTT library.
+func (m mqttOutput) publish(img image.Image, colors []color.RGBA) {
+	if *m.broker == "" {
+		return
+	}
+
+	result := quantize.Measure(img, colors)
+	payload := mqttPayload{Result: result}
+
+	// Measure keeps the palette order, so the dominant color is searched for
+	if len(result.Colors) > 0 {
+		dominant := result.Colors[0]
+		for _, swatch := range result.Colors {
+			if swatch.Proportion > dominant.Proportion {
+				dominant = swatch
+			}
+		}
+		payload.Dominant = dominant.Hex
+		payload.RGB = [3]uint8{dominant.RGBA.R, dominant.RGBA.G, dominant.RGBA.B}
+	}
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		die(err)
+	}
+
+	args := []string{"-h", *m.broker, "-t", *m.topic, "-r", "-s"}
+	if host, port, err := net.SplitHostPort(*m.broker); err == nil {
+		args = []string{"-h", host, "-p", port, "-t", *m.topic, "-r", "-s"}
+	}
+
+	cmd := exec.Command("mosquitto_pub", args...)
+	cmd.Stdin = bytes.NewReader(message)
+
+	if _, err := runCommand(cmd); err != nil {
+		die(err)
+	}
+}
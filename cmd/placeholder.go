@@ -0,0 +1,85 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/joshdk/quantize/placeholder"
+)
+
+// placeholderStyles maps the name of every placeholder style to its value.
+var placeholderStyles = map[string]placeholder.Style{
+	"solid":    placeholder.Solid,
+	"gradient": placeholder.Gradient,
+	"mosaic":   placeholder.Mosaic,
+}
+
+// placeholderOutput holds the flags for writing a placeholder image.
+type placeholderOutput struct {
+	path  *string
+	size  *string
+	style *string
+}
+
+// placeholderFlags registers the flags for writing a placeholder image with
+// the given flag set.
+func placeholderFlags(flags *flag.FlagSet) placeholderOutput {
+	return placeholderOutput{
+		path:  flags.String("placeholder", "", "also write a tiny placeholder image to the given PNG file"),
+		size:  flags.String("size", "32x32", "size of the placeholder image, as WIDTHxHEIGHT"),
+		style: flags.String("placeholder-style", "mosaic", "placeholder style, one of solid, gradient, or mosaic"),
+	}
+}
+
+// write writes the placeholder image for the given image, if requested.
+func (p placeholderOutput) write(img image.Image) {
+	if *p.path == "" {
+		return
+	}
+
+	style, found := placeholderStyles[*p.style]
+	if !found {
+		die(usageError(fmt.Errorf("unknown placeholder style %q", *p.style)))
+	}
+
+	width, height, err := parseSize(*p.size)
+	if err != nil {
+		die(usageError(err))
+	}
+
+	lqip, err := placeholder.Image(img, width, height, style)
+	if err != nil {
+		die(usageError(err))
+	}
+
+	if err := savePNG(*p.path, lqip); err != nil {
+		die(err)
+	}
+}
+
+// parseSize parses a size such as "32x32" into its width and height.
+func parseSize(size string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(size), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	width, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	height, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	return width, height, nil
+}
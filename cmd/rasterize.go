@@ -0,0 +1,33 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// rasterizers maps the extensions of vector formats to the external command
+// that renders the first page of such a file as a PNG written to stdout.
+var rasterizers = map[string]func(path string) *exec.Cmd{
+	".pdf": func(path string) *exec.Cmd {
+		return exec.Command("pdftoppm", "-png", "-singlefile", "-f", "1", "-l", "1", path)
+	},
+	".svg": func(path string) *exec.Cmd {
+		return exec.Command("rsvg-convert", "--format", "png", path)
+	},
+}
+
+// rasterizer returns the command used to rasterize the given file, or false
+// if the file is not a supported vector format.
+func rasterizer(path string) (*exec.Cmd, bool) {
+	command, found := rasterizers[strings.ToLower(filepath.Ext(path))]
+	if !found {
+		return nil, false
+	}
+
+	return command(path), true
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// writeRemapped writes the given image, remapped to the given palette, to a
+// PNG file at the given path, if requested. The image is dithered with
+// Floyd-Steinberg error diffusion, unless dithering is disabled, in which case
+// every pixel is replaced by the nearest palette color.
+func writeRemapped(path string, img image.Image, colors []color.RGBA, dithered bool) {
+	if path == "" {
+		return
+	}
+
+	var remapped *image.Paletted
+	if dithered {
+		remapped = dither(img, colors)
+	} else {
+		remapped = remap(img, colors)
+	}
+
+	if err := savePNG(path, remapped); err != nil {
+		die(err)
+	}
+}
+
+// dither returns the given image remapped to the given palette, with the error
+// of each pixel diffused to its neighbors using Floyd-Steinberg dithering.
+func dither(img image.Image, colors []color.RGBA) *image.Paletted {
+	rect := img.Bounds()
+
+	palette := make(color.Palette, len(colors))
+	for index, clr := range colors {
+		palette[index] = clr
+	}
+
+	paletted := image.NewPaletted(rect, palette)
+	draw.FloydSteinberg.Draw(paletted, rect, img, rect.Min)
+
+	return paletted
+}
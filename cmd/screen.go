@@ -0,0 +1,102 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// screenScript is a PowerShell script that captures the primary display and
+// writes it to stdout as a PNG.
+const screenScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$bounds = [Windows.Forms.Screen]::PrimaryScreen.Bounds
+$img = New-Object Drawing.Bitmap $bounds.Width, $bounds.Height
+$gfx = [Drawing.Graphics]::FromImage($img)
+$gfx.CopyFromScreen($bounds.Location, [Drawing.Point]::Empty, $bounds.Size)
+$buf = New-Object IO.MemoryStream
+$img.Save($buf, [Drawing.Imaging.ImageFormat]::Png)
+$out = [Console]::OpenStandardOutput()
+$out.Write($buf.ToArray(), 0, $buf.Length)`
+
+func screenCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize screen", flag.ContinueOnError)
+	out := outputFlags(flags)
+	region := flags.Bool("select", false, "interactively select a region of the screen to capture")
+	parse(flags, args)
+
+	renderer := out.renderer()
+	levels := parseLevels(flags.Args())
+
+	img, err := captureScreen(*region)
+	if err != nil {
+		die(err)
+	}
+
+	colors := out.colors(img, levels)
+
+	renderer(img, colors)
+	out.show(img, colors)
+
+}
+
+// captureScreen takes a screenshot of the current display, or of a region
+// selected interactively by the user, using the platform's screenshot tool.
+func captureScreen(region bool) (image.Image, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		// screencapture can only write to a file
+		file, err := ioutil.TempFile("", "quantize-*.png")
+		if err != nil {
+			return nil, err
+		}
+		file.Close()
+		defer os.Remove(file.Name())
+
+		args := []string{"-x", "-t", "png"}
+		if region {
+			args = append(args, "-i")
+		}
+
+		if err := exec.Command("screencapture", append(args, file.Name())...).Run(); err != nil {
+			return nil, fmt.Errorf("screencapture: %s", err.Error())
+		}
+		return load(file.Name())
+
+	case "windows":
+		if region {
+			return nil, fmt.Errorf("region selection is not supported on %s", runtime.GOOS)
+		}
+		return decodeCommand(exec.Command("powershell", "-NoProfile", "-Command", screenScript))
+
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			if region {
+				geometry, err := exec.Command("slurp").Output()
+				if err != nil {
+					return nil, fmt.Errorf("slurp: %s", err.Error())
+				}
+				return decodeCommand(exec.Command("grim", "-g", strings.TrimSpace(string(geometry)), "-"))
+			}
+			return decodeCommand(exec.Command("grim", "-"))
+		}
+
+		// ImageMagick's import lets the user select a region by default
+		if region {
+			return decodeCommand(exec.Command("import", "png:-"))
+		}
+		return decodeCommand(exec.Command("import", "-window", "root", "png:-"))
+
+	default:
+		return nil, fmt.Errorf("screen capture is not supported on %s", runtime.GOOS)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/joshdk/quantize"
+)
+
+// indexEntry is the cached palette of a single image, which is reused for as
+// long as the image is unmodified.
+type indexEntry struct {
+	ModTime int64    `json:"mtime"`
+	Size    int64    `json:"size"`
+	Levels  int      `json:"levels"`
+	Palette []string `json:"palette"`
+}
+
+// paletteIndex maps the absolute path of every indexed image to its entry.
+type paletteIndex map[string]indexEntry
+
+func searchCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize search", flag.ContinueOnError)
+	query := flags.String("color", "", "color to search for, such as '#ff6600'")
+	tolerance := flags.Float64("tolerance", 10, "largest perceptual distance between the color and a palette color")
+	levels := flags.Int("levels", 3, "number of levels of each palette to index")
+	cache := flags.String("cache", "", "path to the palette index (default ~/.cache/quantize/index.json)")
+	parse(flags, args)
+
+	if *query == "" {
+		die(usageError(errors.New("color not specified")))
+	}
+
+	target, err := quantize.ParseHex(*query)
+	if err != nil {
+		die(usageError(err))
+	}
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("directory not specified")))
+	}
+
+	if *cache == "" {
+		*cache = defaultCachePath()
+	}
+
+	index, err := readIndex(*cache)
+	if err != nil {
+		die(err)
+	}
+
+	paths, err := imageFiles(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	type match struct {
+		path     string
+		distance float64
+	}
+
+	var (
+		matches []match
+		skipped int
+	)
+	for _, path := range paths {
+		colors, err := index.palette(path, *levels)
+		if err != nil {
+			warn(pathError(path, err))
+			skipped++
+			continue
+		}
+
+		distance := math.Inf(1)
+		for _, clr := range colors {
+			distance = math.Min(distance, quantize.DeltaE(target, clr))
+		}
+
+		if distance <= *tolerance {
+			matches = append(matches, match{path, distance})
+		}
+	}
+
+	if err := writeIndex(*cache, index); err != nil {
+		die(err)
+	}
+
+	// The closest matches are listed first
+	sort.SliceStable(matches, func(i int, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	for _, match := range matches {
+		fmt.Println(match.path)
+	}
+
+	if skipped > 0 {
+		die(partialError(skipped, len(paths)))
+	}
+
+}
+
+// palette returns the palette of the image at the given path, from the index
+// if the image is unmodified since it was indexed, and otherwise by loading
+// the image and updating the index.
+func (index paletteIndex) palette(path string, levels int) ([]color.RGBA, error) {
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, found := index[key]
+	if !found || entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size() || entry.Levels != levels {
+		img, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = indexEntry{
+			ModTime: info.ModTime().UnixNano(),
+			Size:    info.Size(),
+			Levels:  levels,
+		}
+		for _, clr := range quantize.Image(img, levels) {
+			entry.Palette = append(entry.Palette, quantize.Hex(clr))
+		}
+
+		index[key] = entry
+	}
+
+	colors := make([]color.RGBA, len(entry.Palette))
+	for i, hex := range entry.Palette {
+		if colors[i], err = quantize.ParseHex(hex); err != nil {
+			return nil, err
+		}
+	}
+
+	return colors, nil
+}
+
+// defaultCachePath returns the path of the palette index, in the XDG cache
+// directory.
+func defaultCachePath() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+
+	return filepath.Join(dir, "quantize", "index.json")
+}
+
+// readIndex reads the palette index at the given path, which is empty if the
+// index does not exist yet.
+func readIndex(path string) (paletteIndex, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return paletteIndex{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := paletteIndex{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+
+	return index, nil
+}
+
+// writeIndex writes the given palette index to the given path, creating its
+// directory if needed.
+func writeIndex(path string, index paletteIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,222 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+const (
+	// previewSize is the largest dimension, in pixels, of a previewed image.
+	previewSize = 320
+
+	// swatchSize is the size, in pixels, of each previewed palette color.
+	swatchSize = 32
+
+	// sixelLevels is the number of MMCQ levels used to reduce a previewed
+	// image into the limited number of colors a sixel image may use.
+	sixelLevels = 8
+)
+
+// preview displays the given image, followed by a strip of swatches for the
+// given palette, inline in the terminal. The Kitty and iTerm2 graphics
+// protocols are used when the terminal is known to support them, with sixel
+// graphics as the fallback.
+func preview(img image.Image, colors []color.RGBA) error {
+	out := bufio.NewWriter(os.Stdout)
+
+	encode := encodeSixel
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(os.Getenv("TERM"), "kitty"):
+		encode = encodeKitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+		encode = encodeITerm
+	}
+
+	for _, pic := range []image.Image{thumbnail(img, previewSize), swatches(colors)} {
+		if err := encode(out, pic); err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+	}
+
+	return out.Flush()
+}
+
+// thumbnail scales the given image down, using nearest neighbor sampling, so
+// that neither dimension exceeds the given size.
+func thumbnail(img image.Image, size int) image.Image {
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		width, height = size, height*size/width
+	} else {
+		width, height = width*size/height, size
+	}
+	if width == 0 {
+		width = 1
+	}
+	if height == 0 {
+		height = 1
+	}
+
+	return scale(img, width, height)
+}
+
+// scale resizes the given image to the given dimensions, using nearest
+// neighbor sampling.
+func scale(img image.Image, width int, height int) *image.RGBA {
+	rect := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			scaled.Set(x, y, img.At(
+				rect.Min.X+x*rect.Dx()/width,
+				rect.Min.Y+y*rect.Dy()/height,
+			))
+		}
+	}
+
+	return scaled
+}
+
+// swatches renders the given palette as a horizontal strip of squares.
+func swatches(colors []color.RGBA) *image.RGBA {
+	strip := image.NewRGBA(image.Rect(0, 0, swatchSize*len(colors), swatchSize))
+
+	for index, clr := range colors {
+		square := image.Rect(index*swatchSize, 0, (index+1)*swatchSize, swatchSize)
+		draw.Draw(strip, square, image.NewUniform(clr), image.Point{}, draw.Src)
+	}
+
+	return strip
+}
+
+// encodeKitty writes the given image using the Kitty graphics protocol, which
+// transfers a PNG in base64 encoded chunks of at most 4096 bytes.
+func encodeKitty(w io.Writer, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	for first := true; first || len(data) > 0; first = false {
+		chunk := data
+		if len(chunk) > 4096 {
+			chunk = chunk[:4096]
+		}
+		data = data[len(chunk):]
+
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(w, "\x1b_Gf=100,a=T,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	return nil
+}
+
+// encodeITerm writes the given image using the iTerm2 inline image protocol.
+func encodeITerm(w io.Writer, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d:%s\a",
+		buf.Len(), base64.StdEncoding.EncodeToString(buf.Bytes()))
+	return err
+}
+
+// encodeSixel writes the given image as sixel graphics. Since sixel images
+// are paletted, the image is first reduced with MMCQ.
+func encodeSixel(w io.Writer, img image.Image) error {
+	rect := img.Bounds()
+
+	colors := quantize.Image(img, sixelLevels)
+	paletted := remap(img, colors)
+
+	// Introduce the image along with its size and color registers
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", rect.Dx(), rect.Dy())
+	for index, clr := range colors {
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", index,
+			int(clr.R)*100/255, int(clr.G)*100/255, int(clr.B)*100/255)
+	}
+
+	// Each band of sixels covers 6 rows of pixels, drawn one color at a time
+	for top := rect.Min.Y; top < rect.Max.Y; top += 6 {
+		for index := range colors {
+			var band []byte
+			used := false
+
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				var bits byte
+				for row := 0; row < 6 && top+row < rect.Max.Y; row++ {
+					if int(paletted.ColorIndexAt(x, top+row)) == index {
+						bits |= 1 << uint(row)
+					}
+				}
+				used = used || bits != 0
+				band = append(band, 63+bits)
+			}
+
+			if used {
+				fmt.Fprintf(w, "#%d%s$", index, runLength(band))
+			}
+		}
+		fmt.Fprint(w, "-")
+	}
+
+	_, err := fmt.Fprint(w, "\x1b\\")
+	return err
+}
+
+// runLength compresses repeated sixels using the "!<count><sixel>" syntax.
+func runLength(band []byte) string {
+	var buf bytes.Buffer
+
+	for start := 0; start < len(band); {
+		end := start
+		for end < len(band) && band[end] == band[start] {
+			end++
+		}
+
+		if count := end - start; count > 3 {
+			fmt.Fprintf(&buf, "!%d%c", count, band[start])
+		} else {
+			buf.Write(band[start:end])
+		}
+
+		start = end
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,255 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/joshdk/quantize"
+)
+
+// maxPageSize is the largest prefix of a page that is searched for images,
+// since the relevant tags belong in the head.
+const maxPageSize = 1 << 20
+
+// maxSiteImageSize is the largest image that is downloaded from a page.
+const maxSiteImageSize = 32 << 20
+
+// siteClient is used for every request made while inspecting a page, so that
+// a slow server can not hang the command.
+var siteClient = &http.Client{Timeout: 30 * time.Second}
+
+var (
+	// tagPattern matches every link & meta tag within a page.
+	tagPattern = regexp.MustCompile(`(?is)<(link|meta)\b[^>]*>`)
+
+	// attributePattern matches every attribute within a tag.
+	attributePattern = regexp.MustCompile(`(?s)([a-zA-Z:-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// siteImage is an image found on a page.
+type siteImage struct {
+	Source   string            `json:"source"`
+	URL      string            `json:"url"`
+	Dominant string            `json:"dominant"`
+	Palette  []quantize.Swatch `json:"palette"`
+}
+
+func siteCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize site", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the images and their palettes as JSON")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("page URL not specified")))
+	}
+
+	levels := parseLevels(args[1:])
+
+	page, err := url.Parse(args[0])
+	if err != nil || (page.Scheme != "http" && page.Scheme != "https") {
+		die(usageError(fmt.Errorf("invalid page URL %q", args[0])))
+	}
+
+	sources, err := siteSources(page)
+	if err != nil {
+		die(err)
+	}
+
+	var images []siteImage
+	for _, source := range sources {
+		img, err := fetchImage(source.URL)
+		if err != nil {
+			// The default favicon location is only a guess
+			if source.Source != "favicon.ico" {
+				warn(err)
+			}
+			continue
+		}
+
+		source.Palette = opaquePalette(img, levels)
+		if len(source.Palette) > 0 {
+			source.Dominant = source.Palette[0].Hex
+		}
+
+		images = append(images, source)
+	}
+
+	if len(images) == 0 {
+		die(fmt.Errorf("%s: no favicon or preview image found", page))
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(images); err != nil {
+			die(err)
+		}
+		return
+	}
+
+	for index, img := range images {
+		if index > 0 {
+			fmt.Println()
+		}
+
+		hexes := make([]string, len(img.Palette))
+		for index, swatch := range img.Palette {
+			hexes[index] = swatch.Hex
+		}
+
+		fmt.Printf("%s: %s\n", img.Source, img.URL)
+		fmt.Printf("dominant: %s\n", img.Dominant)
+		fmt.Printf("palette: %s\n", strings.Join(hexes, " "))
+	}
+
+}
+
+// siteSources fetches the given page, and returns the favicon & preview
+// images that it declares. When no favicon is declared, the default location
+// at the root of the site is returned instead.
+func siteSources(page *url.URL) ([]siteImage, error) {
+
+	body, err := fetch(page.String())
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sources []siteImage
+		icon    bool
+	)
+
+	add := func(source string, href string) {
+		if ref, err := page.Parse(strings.TrimSpace(href)); err == nil && href != "" {
+			sources = append(sources, siteImage{Source: source, URL: ref.String()})
+		}
+	}
+
+	for _, tag := range tagPattern.FindAllStringSubmatch(string(data), -1) {
+		attributes := map[string]string{}
+		for _, attribute := range attributePattern.FindAllStringSubmatch(tag[0], -1) {
+			attributes[strings.ToLower(attribute[1])] = strings.Trim(attribute[2], `"'`)
+		}
+
+		switch strings.ToLower(tag[1]) {
+		case "link":
+			// The rel attribute is a list, such as "shortcut icon"
+			for _, rel := range strings.Fields(strings.ToLower(attributes["rel"])) {
+				if rel == "icon" || rel == "apple-touch-icon" {
+					add(rel, attributes["href"])
+					icon = true
+					break
+				}
+			}
+
+		case "meta":
+			property := strings.ToLower(attributes["property"] + attributes["name"])
+			if property == "og:image" || property == "twitter:image" {
+				add(property, attributes["content"])
+			}
+		}
+	}
+
+	if !icon {
+		add("favicon.ico", "/favicon.ico")
+	}
+
+	return sources, nil
+}
+
+// fetch requests the given URL, and returns the response body if successful.
+func fetch(address string) (io.ReadCloser, error) {
+	response, err := siteClient.Get(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("%s: %s", address, response.Status)
+	}
+
+	return response.Body, nil
+}
+
+// fetchImage downloads and decodes the image at the given URL. The download
+// is limited in size, and the image header is checked against the default
+// decode limits before the image is fully decoded.
+func fetchImage(address string) (image.Image, error) {
+	body, err := fetch(address)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxSiteImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", address, err.Error())
+	}
+	if len(data) > maxSiteImageSize {
+		return nil, fmt.Errorf("%s: image exceeds %d bytes", address, maxSiteImageSize)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", address, err.Error())
+	}
+	if config.Width > quantize.DefaultMaxDimension || config.Height > quantize.DefaultMaxDimension ||
+		int64(config.Width)*int64(config.Height) > quantize.DefaultMaxPixels {
+		return nil, fmt.Errorf("%s: %s", address, quantize.ErrImageTooLarge.Error())
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", address, err.Error())
+	}
+
+	return img, nil
+}
+
+// opaquePalette performs MMCQ on the opaque pixels of the given image, since
+// icons commonly have transparent backgrounds, and returns a swatch for every
+// palette color in order of decreasing proportion.
+func opaquePalette(img image.Image, levels int) []quantize.Swatch {
+	if levels == autoLevels {
+		levels = quantize.LevelsAuto(img)
+	}
+
+	quantizer := quantize.NewQuantizer(quantize.WithPixelFilter(func(x int, y int, _ color.RGBA) bool {
+		_, _, _, a := img.At(x, y).RGBA()
+		return a >= 0x8000
+	}))
+
+	result, err := quantizer.Analyze(img, levels)
+	if err != nil {
+		die(usageError(err))
+	}
+
+	return result.Colors
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/joshdk/quantize"
+)
+
+const (
+	// svgSwatch is the width & height of every swatch in an SVG palette.
+	svgSwatch = 120
+
+	// svgColumns is the largest number of swatches in each row of an SVG
+	// palette, so that large palettes wrap into a grid.
+	svgColumns = 8
+)
+
+// renderSVG prints the given palette as an SVG grid of swatches, each labeled
+// with its hex value and annotated with data attributes for tooling.
+func renderSVG(colors []color.RGBA) {
+	cols := len(colors)
+	if cols > svgColumns {
+		cols = svgColumns
+	}
+	rows := (len(colors) + svgColumns - 1) / svgColumns
+
+	fmt.Printf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" data-colors=\"%d\">\n",
+		cols*svgSwatch, rows*svgSwatch, cols*svgSwatch, rows*svgSwatch, len(colors))
+
+	for index, clr := range colors {
+		x, y := index%svgColumns*svgSwatch, index/svgColumns*svgSwatch
+		hex := quantize.Hex(clr)
+		hsl := quantize.ToHSL(clr)
+
+		fmt.Printf("  <g data-index=\"%d\" data-hex=\"%s\" data-rgb=\"%d,%d,%d\" data-hsl=\"%.0f,%.0f%%,%.0f%%\">\n",
+			index, hex, clr.R, clr.G, clr.B, hsl.H, hsl.S*100, hsl.L*100)
+		fmt.Printf("    <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+			x, y, svgSwatch, svgSwatch, hex)
+		fmt.Printf("    <text x=\"%d\" y=\"%d\" fill=\"%s\" font-family=\"monospace\" font-size=\"14\" text-anchor=\"middle\">%s</text>\n",
+			x+svgSwatch/2, y+svgSwatch-12, quantize.Hex(label(clr)), hex)
+		fmt.Println("  </g>")
+	}
+
+	fmt.Println("</svg>")
+}
+
+// label returns black or white, whichever contrasts most with the given
+// background color.
+func label(background color.RGBA) color.RGBA {
+	black := color.RGBA{0, 0, 0, 0xFF}
+	white := color.RGBA{255, 255, 255, 0xFF}
+
+	if quantize.Contrast(background, black) >= quantize.Contrast(background, white) {
+		return black
+	}
+	return white
+}
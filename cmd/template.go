@@ -0,0 +1,59 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"text/template"
+
+	"github.com/joshdk/quantize"
+)
+
+// templateColor is the data made available to output templates for each
+// color in a palette.
+type templateColor struct {
+	Index int
+	Hex   string
+	R     uint8
+	G     uint8
+	B     uint8
+	HSL   quantize.HSL
+
+	// Population is the number of pixels of the image nearest to the color,
+	// and Proportion is the fraction of the image that they cover.
+	Population int
+	Proportion float64
+}
+
+// templateRenderer parses the given template, and returns a function that
+// renders it once per color of the palette of an image, with each rendering on
+// its own line.
+func templateRenderer(text string) (func(image.Image, []color.RGBA), error) {
+	tmpl, err := template.New("output").Parse(text + "\n")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(img image.Image, colors []color.RGBA) {
+		for index, swatch := range quantize.Measure(img, colors).Colors {
+			data := templateColor{
+				Index:      index,
+				Hex:        swatch.Hex,
+				R:          swatch.RGBA.R,
+				G:          swatch.RGBA.G,
+				B:          swatch.RGBA.B,
+				HSL:        swatch.HSL,
+				Population: swatch.Population,
+				Proportion: swatch.Proportion,
+			}
+
+			if err := tmpl.Execute(os.Stdout, data); err != nil {
+				die(err)
+			}
+		}
+	}, nil
+}
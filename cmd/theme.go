@@ -0,0 +1,93 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"text/template"
+
+	"github.com/joshdk/quantize"
+)
+
+// themeTemplates maps the name of every supported terminal to a template that
+// renders a theme in the format of its configuration file.
+var themeTemplates = map[string]string{
+	"xresources": `*.background: {{hex .Background}}
+*.foreground: {{hex .Foreground}}
+*.cursorColor: {{hex .Foreground}}
+{{range $index, $color := .Colors}}*.color{{$index}}: {{hex $color}}
+{{end}}`,
+
+	"alacritty": `[colors.primary]
+background = "{{hex .Background}}"
+foreground = "{{hex .Foreground}}"
+
+[colors.normal]
+{{range $index, $name := names}}{{$name}} = "{{hex (index $.Colors $index)}}"
+{{end}}
+[colors.bright]
+{{range $index, $name := names}}{{$name}} = "{{hex (index $.Colors (bright $index))}}"
+{{end}}`,
+
+	"kitty": `background {{hex .Background}}
+foreground {{hex .Foreground}}
+cursor {{hex .Foreground}}
+{{range $index, $color := .Colors}}color{{$index}} {{hex $color}}
+{{end}}`,
+
+	"wezterm": `[colors]
+background = "{{hex .Background}}"
+foreground = "{{hex .Foreground}}"
+cursor_bg = "{{hex .Foreground}}"
+ansi = [{{range $index, $color := .Colors}}{{if lt $index 8}}{{if $index}}, {{end}}"{{hex $color}}"{{end}}{{end}}]
+brights = [{{range $index, $color := .Colors}}{{if ge $index 8}}{{if ne $index 8}}, {{end}}"{{hex $color}}"{{end}}{{end}}]
+`,
+}
+
+// themeFuncs are the functions made available to theme templates.
+var themeFuncs = template.FuncMap{
+	"hex": func(clr color.RGBA) string {
+		return quantize.Hex(clr)
+	},
+	"names": func() []string {
+		return []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	},
+	"bright": func(index int) int {
+		return index + 8
+	},
+}
+
+func themeCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize theme", flag.ContinueOnError)
+	format := flags.String("format", "xresources", "theme format, one of xresources, alacritty, kitty, or wezterm")
+	parse(flags, args)
+
+	text, found := themeTemplates[*format]
+	if !found {
+		die(usageError(fmt.Errorf("unknown theme format %q", *format)))
+	}
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("image file not specified")))
+	}
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	tmpl := template.Must(template.New(*format).Funcs(themeFuncs).Parse(text))
+
+	if err := tmpl.Execute(os.Stdout, quantize.NewTheme(img)); err != nil {
+		die(err)
+	}
+
+}
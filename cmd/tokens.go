@@ -0,0 +1,52 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+
+	"github.com/joshdk/quantize"
+)
+
+// renderTokens prints the given palette as a W3C design tokens JSON document,
+// with each color named by its step on a lightest to darkest scale.
+func renderTokens(colors []color.RGBA) {
+	sorted, names := steps(colors)
+
+	fmt.Println(`{`)
+	fmt.Println(`  "palette": {`)
+	for index, clr := range sorted {
+		separator := ","
+		if index == len(sorted)-1 {
+			separator = ""
+		}
+		fmt.Printf("    %q: {\"$type\": \"color\", \"$value\": %q}%s\n",
+			names[index], quantize.Hex(clr), separator)
+	}
+	fmt.Println(`  }`)
+	fmt.Println(`}`)
+}
+
+// steps sorts the given palette from lightest to darkest, and names each color
+// by its step on the scale, as 100, 200, 300, and so on.
+func steps(colors []color.RGBA) ([]color.RGBA, []string) {
+
+	sorted := make([]color.RGBA, len(colors))
+	copy(sorted, colors)
+
+	sort.SliceStable(sorted, func(i int, j int) bool {
+		return quantize.Luminance(sorted[i]) > quantize.Luminance(sorted[j])
+	})
+
+	names := make([]string, len(sorted))
+	for index := range sorted {
+		names[index] = strconv.Itoa((index + 1) * 100)
+	}
+
+	return sorted, names
+}
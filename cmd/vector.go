@@ -0,0 +1,55 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joshdk/quantize/vectorize"
+)
+
+func vectorCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize vector", flag.ContinueOnError)
+	tolerance := flags.Float64("tolerance", 1, "largest distance in pixels that simplified outlines may stray from the pixels")
+	fixed := flags.String("palette", "", "fixed palette to remap to instead of quantizing, as an .act, .gpl, .hex, or .pal file, or a list such as '#112233,#445566'")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 2 {
+		die(usageError(errors.New("image file and output file not specified")))
+	}
+	if *tolerance < 0 {
+		die(usageError(fmt.Errorf("invalid tolerance %g", *tolerance)))
+	}
+
+	levels := parseLevels(args[2:])
+
+	img, err := load(args[0])
+	if err != nil {
+		die(err)
+	}
+
+	colors := output{palette: fixed}.colors(img, levels)
+	layers := vectorize.Trace(img, colors, *tolerance)
+
+	file, err := os.Create(args[1])
+	if err != nil {
+		die(err)
+	}
+
+	if err := vectorize.EncodeSVG(file, layers, img.Bounds().Dx(), img.Bounds().Dy()); err != nil {
+		file.Close()
+		die(err)
+	}
+
+	if err := file.Close(); err != nil {
+		die(err)
+	}
+
+}
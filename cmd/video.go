@@ -0,0 +1,131 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/joshdk/quantize"
+)
+
+func videoCommand(args []string) {
+
+	flags := flag.NewFlagSet("quantize video", flag.ContinueOnError)
+	every := flags.Duration("every", 2*time.Second, "interval between sampled frames")
+	fixed := flags.String("palette", "", "fixed palette to remap to instead of quantizing, as an .act, .gpl, .hex, or .pal file, or a list such as '#112233,#445566'")
+	strips := flags.String("barcode", "", "also write a movie barcode, with one strip of the palette colors per sampled frame, to the given PNG file")
+	size := flags.String("strip", "2x256", "size of every strip of the movie barcode, as WIDTHxHEIGHT")
+	parse(flags, args)
+
+	args = flags.Args()
+	if len(args) < 1 {
+		die(usageError(errors.New("video file not specified")))
+	}
+	if *every <= 0 {
+		die(usageError(fmt.Errorf("invalid interval %s", *every)))
+	}
+
+	width, height, err := parseSize(*size)
+	if err != nil || width <= 0 || height <= 0 {
+		die(usageError(fmt.Errorf("invalid strip size %q", *size)))
+	}
+
+	levels := parseLevels(args[1:])
+	var timeline []quantize.Result
+
+	err = frames(args[0], *every, func(timestamp time.Duration, img image.Image) {
+		colors := output{palette: fixed}.colors(img, levels)
+
+		hexes := make([]string, len(colors))
+		for index, clr := range colors {
+			hexes[index] = quantize.Hex(clr)
+		}
+
+		fmt.Printf("%s %s\n", timestamp, strings.Join(hexes, " "))
+
+		if *strips != "" {
+			timeline = append(timeline, quantize.Measure(img, colors))
+		}
+	})
+	if err != nil {
+		die(err)
+	}
+
+	if *strips != "" {
+		if err := savePNG(*strips, barcode(timeline, width, height)); err != nil {
+			die(err)
+		}
+	}
+
+}
+
+// frames decodes a frame of the video at the given path at every interval
+// using ffmpeg, and calls the given function with each frame and its
+// timestamp as soon as it is decoded.
+func frames(path string, every time.Duration, fn func(time.Duration, image.Image)) error {
+	return ffmpegFrames([]string{"-i", path}, every, fn)
+}
+
+// ffmpegFrames decodes a frame of the ffmpeg input given by the input
+// arguments at every interval, and calls the given function with each frame
+// and its timestamp as soon as it is decoded.
+func ffmpegFrames(input []string, every time.Duration, fn func(time.Duration, image.Image)) error {
+
+	args := append([]string{"-loglevel", "error"}, input...)
+	cmd := exec.Command("ffmpeg", append(args,
+		"-vf", fmt.Sprintf("fps=1/%g", every.Seconds()),
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"-",
+	)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return fmt.Errorf("%s is required but could not be found", cmd.Args[0])
+		}
+		return err
+	}
+
+	// Frames are written back to back as PNG images
+	reader := bufio.NewReader(stdout)
+	for index := 0; ; index++ {
+		if _, err := reader.Peek(1); err == io.EOF {
+			break
+		}
+
+		img, err := png.Decode(reader)
+		if err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return decodeError(err)
+		}
+
+		fn(time.Duration(index)*every, img)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s: %s", cmd.Args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image/color"
+	"math"
+)
+
+// HSL is a color in the hue, saturation, & lightness color space. Hue is in
+// degrees within [0, 360), while saturation and lightness are within [0, 1].
+type HSL struct {
+	H float64 `json:"h" yaml:"h"`
+	S float64 `json:"s" yaml:"s"`
+	L float64 `json:"l" yaml:"l"`
+}
+
+// HSV is a color in the hue, saturation, & value color space. Hue is in
+// degrees within [0, 360), while saturation and value are within [0, 1].
+type HSV struct {
+	H float64 `json:"h" yaml:"h"`
+	S float64 `json:"s" yaml:"s"`
+	V float64 `json:"v" yaml:"v"`
+}
+
+// Lab is a color in the CIE L*a*b* color space, using a D65 white point.
+// Lightness is within [0, 100].
+type Lab struct {
+	L float64 `json:"l" yaml:"l"`
+	A float64 `json:"a" yaml:"a"`
+	B float64 `json:"b" yaml:"b"`
+}
+
+// ToHSL takes in an RGB color, and returns its HSL representation.
+func ToHSL(clr color.RGBA) HSL {
+	r, g, b := float64(clr.R)/255, float64(clr.G)/255, float64(clr.B)/255
+
+	high := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+	lightness := (high + low) / 2
+
+	// Shades of gray have no hue or saturation
+	if high == low {
+		return HSL{0, 0, lightness}
+	}
+
+	delta := high - low
+	saturation := delta / (1 - math.Abs(2*lightness-1))
+
+	return HSL{hue(r, g, b, high, delta), saturation, lightness}
+}
+
+// ToHSV takes in an RGB color, and returns its HSV representation.
+func ToHSV(clr color.RGBA) HSV {
+	r, g, b := float64(clr.R)/255, float64(clr.G)/255, float64(clr.B)/255
+
+	high := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+
+	// Shades of gray have no hue or saturation
+	if high == low {
+		return HSV{0, 0, high}
+	}
+
+	delta := high - low
+
+	return HSV{hue(r, g, b, high, delta), delta / high, high}
+}
+
+// ToLab takes in an RGB color, and returns its CIE L*a*b* representation.
+func ToLab(clr color.RGBA) Lab {
+	r, g, b := Linearize(clr.R), Linearize(clr.G), Linearize(clr.B)
+
+	// Convert from linear sRGB into CIE XYZ, normalized by the D65 white point
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := (0.2126729*r + 0.7151522*g + 0.0721750*b) / 1.00000
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	fx, fy, fz := labF(x), labF(y), labF(z)
+
+	return Lab{
+		116*fy - 16,
+		500 * (fx - fy),
+		200 * (fy - fz),
+	}
+}
+
+// FromLab takes in a CIE L*a*b* color, and returns its RGB representation.
+// Colors outside of the sRGB gamut are clamped.
+func FromLab(lab Lab) color.RGBA {
+	fy := (lab.L + 16) / 116
+	fx := fy + lab.A/500
+	fz := fy - lab.B/200
+
+	// Convert from CIE XYZ, scaled by the D65 white point, into linear sRGB
+	x := labFInverse(fx) * 0.95047
+	y := labFInverse(fy) * 1.00000
+	z := labFInverse(fz) * 1.08883
+
+	return color.RGBA{
+		Delinearize(3.2404542*x - 1.5371385*y - 0.4985314*z),
+		Delinearize(-0.9692660*x + 1.8760108*y + 0.0415560*z),
+		Delinearize(0.0556434*x - 0.2040259*y + 1.0572252*z),
+		0xFF,
+	}
+}
+
+// DeltaE takes in two RGB colors, and returns the perceptual distance between
+// them, as the CIE76 euclidean distance between their L*a*b* representations.
+// A distance of about 2.3 corresponds to a just noticeable difference.
+func DeltaE(first color.RGBA, second color.RGBA) float64 {
+	a, b := ToLab(first), ToLab(second)
+
+	dL, dA, dB := a.L-b.L, a.A-b.A, a.B-b.B
+
+	return math.Sqrt(dL*dL + dA*dA + dB*dB)
+}
+
+// Luminance takes in an RGB color, and returns its relative luminance within
+// [0, 1], as defined by WCAG.
+func Luminance(clr color.RGBA) float64 {
+	return 0.2126*Linearize(clr.R) + 0.7152*Linearize(clr.G) + 0.0722*Linearize(clr.B)
+}
+
+// Contrast takes in two RGB colors, and returns their contrast ratio within
+// [1, 21], as defined by WCAG. The order of the colors does not matter.
+func Contrast(first color.RGBA, second color.RGBA) float64 {
+	lighter, darker := Luminance(first), Luminance(second)
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+
+	return (lighter + 0.05) / (darker + 0.05)
+}
+
+// Mix takes in two RGB colors, and returns the color the given fraction of
+// the way from the first to the second, interpolated in sRGB.
+func Mix(first color.RGBA, second color.RGBA, fraction float64) color.RGBA {
+	fraction = math.Max(0, math.Min(1, fraction))
+
+	mix := func(a uint8, b uint8) uint8 {
+		return uint8(math.Floor(float64(a) + (float64(b)-float64(a))*fraction + 0.5))
+	}
+
+	return color.RGBA{
+		mix(first.R, second.R),
+		mix(first.G, second.G),
+		mix(first.B, second.B),
+		0xFF,
+	}
+}
+
+// hue returns the hue angle in degrees, shared by both HSL and HSV.
+func hue(r float64, g float64, b float64, high float64, delta float64) float64 {
+	var h float64
+
+	switch high {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+
+	return h
+}
+
+// Linearize converts an 8-bit sRGB component into linear light within [0, 1].
+func Linearize(component uint8) float64 {
+	c := float64(component) / 255
+
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// Delinearize converts linear light within [0, 1] into an 8-bit sRGB
+// component, clamping values outside of that range.
+func Delinearize(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+
+	return uint8(math.Floor(math.Max(0, math.Min(1, c))*255 + 0.5))
+}
+
+// labF is the nonlinear transfer function used by CIE L*a*b*.
+func labF(t float64) float64 {
+	const epsilon = 216.0 / 24389.0
+	const kappa = 24389.0 / 27.0
+
+	if t > epsilon {
+		return math.Cbrt(t)
+	}
+	return (kappa*t + 16) / 116
+}
+
+// labFInverse is the inverse of labF.
+func labFInverse(t float64) float64 {
+	const epsilon = 216.0 / 24389.0
+	const kappa = 24389.0 / 27.0
+
+	if cube := t * t * t; cube > epsilon {
+		return cube
+	}
+	return (116*t - 16) / kappa
+}
@@ -0,0 +1,310 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorSpaces(t *testing.T) {
+
+	tests := []struct {
+		title string
+		color color.RGBA
+		hsl   HSL
+		hsv   HSV
+		lab   Lab
+	}{
+		{
+			title: "black",
+			color: color.RGBA{0, 0, 0, 0xFF},
+			hsl:   HSL{0, 0, 0},
+			hsv:   HSV{0, 0, 0},
+			lab:   Lab{0, 0, 0},
+		},
+		{
+			title: "white",
+			color: color.RGBA{255, 255, 255, 0xFF},
+			hsl:   HSL{0, 0, 1},
+			hsv:   HSV{0, 0, 1},
+			lab:   Lab{100, 0, 0},
+		},
+		{
+			title: "red",
+			color: color.RGBA{255, 0, 0, 0xFF},
+			hsl:   HSL{0, 1, 0.5},
+			hsv:   HSV{0, 1, 1},
+			lab:   Lab{53.24, 80.09, 67.20},
+		},
+		{
+			title: "green",
+			color: color.RGBA{0, 255, 0, 0xFF},
+			hsl:   HSL{120, 1, 0.5},
+			hsv:   HSV{120, 1, 1},
+			lab:   Lab{87.73, -86.18, 83.18},
+		},
+		{
+			title: "blue",
+			color: color.RGBA{0, 0, 255, 0xFF},
+			hsl:   HSL{240, 1, 0.5},
+			hsv:   HSV{240, 1, 1},
+			lab:   Lab{32.30, 79.19, -107.86},
+		},
+		{
+			title: "magenta ignores alpha",
+			color: color.RGBA{255, 0, 255, 0},
+			hsl:   HSL{300, 1, 0.5},
+			hsv:   HSV{300, 1, 1},
+			lab:   Lab{60.32, 98.23, -60.82},
+		},
+		{
+			title: "random values",
+			color: color.RGBA{105, 32, 165, 0xFF},
+			hsl:   HSL{272.93, 0.6751, 0.3863},
+			hsv:   HSV{272.93, 0.8061, 0.6471},
+			lab:   Lab{31.24, 54.87, -57.11},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			hsl := ToHSL(test.color)
+			assert.InDelta(t, test.hsl.H, hsl.H, 0.01)
+			assert.InDelta(t, test.hsl.S, hsl.S, 0.0001)
+			assert.InDelta(t, test.hsl.L, hsl.L, 0.0001)
+
+			hsv := ToHSV(test.color)
+			assert.InDelta(t, test.hsv.H, hsv.H, 0.01)
+			assert.InDelta(t, test.hsv.S, hsv.S, 0.0001)
+			assert.InDelta(t, test.hsv.V, hsv.V, 0.0001)
+
+			lab := ToLab(test.color)
+			assert.InDelta(t, test.lab.L, lab.L, 0.01)
+			assert.InDelta(t, test.lab.A, lab.A, 0.01)
+			assert.InDelta(t, test.lab.B, lab.B, 0.01)
+
+			clr := FromLab(lab)
+			assert.Equal(t, color.RGBA{test.color.R, test.color.G, test.color.B, 0xFF}, clr)
+
+		})
+	}
+
+}
+
+func TestFromLab(t *testing.T) {
+
+	tests := []struct {
+		title string
+		lab   Lab
+		color color.RGBA
+	}{
+		{
+			title: "mid gray",
+			lab:   Lab{53.59, 0, 0},
+			color: color.RGBA{128, 128, 128, 0xFF},
+		},
+		{
+			title: "beyond white",
+			lab:   Lab{120, 0, 0},
+			color: color.RGBA{255, 255, 255, 0xFF},
+		},
+		{
+			title: "below black",
+			lab:   Lab{-10, 0, 0},
+			color: color.RGBA{0, 0, 0, 0xFF},
+		},
+		{
+			title: "out of gamut green",
+			lab:   Lab{50, -120, 0},
+			color: color.RGBA{0, 154, 117, 0xFF},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.color, FromLab(test.lab))
+
+		})
+	}
+
+	// Every color survives a round trip
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				clr := color.RGBA{uint8(r), uint8(g), uint8(b), 0xFF}
+				assert.Equal(t, clr, FromLab(ToLab(clr)))
+			}
+		}
+	}
+
+}
+
+func TestDeltaE(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		first  color.RGBA
+		second color.RGBA
+		delta  float64
+	}{
+		{
+			title:  "identical colors",
+			first:  color.RGBA{105, 32, 165, 0xFF}, // random values
+			second: color.RGBA{105, 32, 165, 0xFF},
+			delta:  0,
+		},
+		{
+			title:  "black and white",
+			first:  color.RGBA{0, 0, 0, 0xFF},
+			second: color.RGBA{255, 255, 255, 0xFF},
+			delta:  100,
+		},
+		{
+			title:  "red and blue",
+			first:  color.RGBA{255, 0, 0, 0xFF},
+			second: color.RGBA{0, 0, 255, 0xFF},
+			delta:  176.31,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.InDelta(t, test.delta, DeltaE(test.first, test.second), 0.01)
+			assert.InDelta(t, test.delta, DeltaE(test.second, test.first), 0.01)
+
+		})
+	}
+
+}
+
+func TestLuminance(t *testing.T) {
+
+	tests := []struct {
+		title     string
+		color     color.RGBA
+		luminance float64
+	}{
+		{
+			title:     "black",
+			color:     color.RGBA{0, 0, 0, 0xFF},
+			luminance: 0,
+		},
+		{
+			title:     "white",
+			color:     color.RGBA{255, 255, 255, 0xFF},
+			luminance: 1,
+		},
+		{
+			title:     "mid gray",
+			color:     color.RGBA{128, 128, 128, 0xFF},
+			luminance: 0.2159,
+		},
+		{
+			title:     "green",
+			color:     color.RGBA{0, 255, 0, 0xFF},
+			luminance: 0.7152,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.InDelta(t, test.luminance, Luminance(test.color), 0.0001)
+
+		})
+	}
+
+}
+
+func TestLinearize(t *testing.T) {
+
+	assert.Equal(t, 0.0, Linearize(0))
+	assert.Equal(t, 1.0, Linearize(255))
+	assert.InDelta(t, 0.2159, Linearize(128), 0.0001)
+
+	// Every component survives a round trip through linear light
+	for component := 0; component < 256; component++ {
+		assert.Equal(t, uint8(component), Delinearize(Linearize(uint8(component))))
+	}
+
+	// Values outside of [0, 1] are clamped
+	assert.Equal(t, uint8(0), Delinearize(-0.5))
+	assert.Equal(t, uint8(255), Delinearize(1.5))
+
+}
+
+func TestContrast(t *testing.T) {
+
+	tests := []struct {
+		title    string
+		first    color.RGBA
+		second   color.RGBA
+		contrast float64
+	}{
+		{
+			title:    "black on white",
+			first:    color.RGBA{0, 0, 0, 0xFF},
+			second:   color.RGBA{255, 255, 255, 0xFF},
+			contrast: 21,
+		},
+		{
+			title:    "white on black",
+			first:    color.RGBA{255, 255, 255, 0xFF},
+			second:   color.RGBA{0, 0, 0, 0xFF},
+			contrast: 21,
+		},
+		{
+			title:    "identical",
+			first:    color.RGBA{0x13, 0x25, 0x5c, 0xFF},
+			second:   color.RGBA{0x13, 0x25, 0x5c, 0xFF},
+			contrast: 1,
+		},
+		{
+			title:    "mid gray on black",
+			first:    color.RGBA{128, 128, 128, 0xFF},
+			second:   color.RGBA{0, 0, 0, 0xFF},
+			contrast: 5.318,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.InDelta(t, test.contrast, Contrast(test.first, test.second), 0.001)
+
+		})
+	}
+
+}
+
+func TestMix(t *testing.T) {
+
+	black := color.RGBA{0, 0, 0, 0xFF}
+	white := color.RGBA{255, 255, 255, 0xFF}
+
+	assert.Equal(t, black, Mix(black, white, 0))
+	assert.Equal(t, white, Mix(black, white, 1))
+	assert.Equal(t, color.RGBA{128, 128, 128, 0xFF}, Mix(black, white, 0.5))
+	assert.Equal(t, color.RGBA{64, 64, 64, 0xFF}, Mix(white, black, 0.75))
+	assert.Equal(t, white, Mix(black, white, 3))
+
+}
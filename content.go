@@ -0,0 +1,207 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Content is the kind of content an image likely holds.
+type Content int
+
+const (
+	// Photo is a photograph, or any image with smooth gradients and noise.
+	Photo Content = iota
+
+	// Graphic is a screenshot, illustration, logo, or any image made up of a
+	// limited number of flat colors.
+	Graphic
+)
+
+func (c Content) String() string {
+	switch c {
+	case Photo:
+		return "photo"
+	case Graphic:
+		return "graphic"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// classifyColors is the largest number of distinct colors counted before
+	// an image is considered to hold too many to be a graphic.
+	classifyColors = 4096
+
+	// classifyPeaks is the number of most common colors whose share of the
+	// pixels measures the peakiness of the histogram.
+	classifyPeaks = 16
+
+	// classifyEdge is the smallest difference in any color component between
+	// neighboring pixels that counts as a hard edge.
+	classifyEdge = 32
+)
+
+// Classification holds the measurements used to classify the content of an
+// image.
+type Classification struct {
+	Content Content `json:"content" yaml:"content"`
+
+	// Colors is the number of distinct colors, counted up to 4096.
+	Colors int `json:"colors" yaml:"colors"`
+
+	// Peakiness is the fraction of the pixels covered by the 16 most common
+	// colors, within [0, 1].
+	Peakiness float64 `json:"peakiness" yaml:"peakiness"`
+
+	// EdgeDensity is the fraction of neighboring pixels that differ sharply,
+	// within [0, 1].
+	EdgeDensity float64 `json:"edge_density" yaml:"edge_density"`
+
+	// Flatness is the fraction of neighboring pixels that are identical,
+	// within [0, 1].
+	Flatness float64 `json:"flatness" yaml:"flatness"`
+}
+
+// Classify takes in an image, and reports whether it is likely a photo, or a
+// graphic such as a screenshot or illustration. Graphics hold few distinct
+// colors, or have most of their pixels in a handful of colors and large flat
+// areas separated by hard edges.
+func Classify(img image.Image) Classification {
+	rect := img.Bounds()
+	height := rect.Dy()
+	pixels := extract(nil, img, rect)
+
+	counts := make(map[color.RGBA]int)
+	var pairs, edges, flat int
+
+	for index, pixel := range pixels {
+		if _, found := counts[pixel]; found || len(counts) < classifyColors {
+			counts[pixel]++
+		}
+
+		// Pixels are extracted column by column, so the pixel to the left is
+		// one column earlier
+		if index < height {
+			continue
+		}
+
+		pairs++
+		switch left := pixels[index-height]; {
+		case left == pixel:
+			flat++
+		case difference(left, pixel) >= classifyEdge:
+			edges++
+		}
+	}
+
+	classification := Classification{Colors: len(counts)}
+	if len(pixels) == 0 {
+		classification.Content = Graphic
+		return classification
+	}
+
+	classification.Peakiness = float64(peaks(counts, classifyPeaks)) / float64(len(pixels))
+	if pairs > 0 {
+		classification.EdgeDensity = float64(edges) / float64(pairs)
+		classification.Flatness = float64(flat) / float64(pairs)
+	}
+
+	switch {
+	case classification.Colors <= 256:
+		classification.Content = Graphic
+	case classification.Peakiness >= 0.8 && classification.Flatness >= 0.5:
+		classification.Content = Graphic
+	default:
+		classification.Content = Photo
+	}
+
+	return classification
+}
+
+// Auto takes in an image, and chooses how to find its palette by classifying
+// its content. Graphics keep their exact colors, taking the most common
+// distinct colors rather than averages that blend in antialiasing, while
+// photos are quantized with MMCQ. The palette may be shorter than 2^levels
+// for graphics with fewer colors. Levels outside of [0, MaxLevels] are
+// clamped.
+func Auto(img image.Image, levels int) []color.RGBA {
+	levels = clampLevels(levels)
+
+	if Classify(img).Content == Photo {
+		return Image(img, levels)
+	}
+
+	counts := make(map[color.RGBA]int)
+	for _, pixel := range extract(nil, img, img.Bounds()) {
+		counts[pixel]++
+	}
+
+	colors := common(counts)
+	if len(colors) == 0 {
+		return Image(img, levels)
+	}
+	if target := 1 << uint(levels); len(colors) > target {
+		colors = colors[:target]
+	}
+
+	return colors
+}
+
+// common returns the given colors from most to least common, breaking ties by
+// their hex value so that the order is deterministic.
+func common(counts map[color.RGBA]int) []color.RGBA {
+	colors := make([]color.RGBA, 0, len(counts))
+	for clr := range counts {
+		colors = append(colors, clr)
+	}
+
+	sort.Slice(colors, func(i int, j int) bool {
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+		return Hex(colors[i]) < Hex(colors[j])
+	})
+
+	return colors
+}
+
+// peaks returns the total count of the given number of most common colors.
+func peaks(counts map[color.RGBA]int, n int) int {
+	var total int
+
+	for index, clr := range common(counts) {
+		if index >= n {
+			break
+		}
+		total += counts[clr]
+	}
+
+	return total
+}
+
+// difference returns the largest difference between any color component of
+// the given colors.
+func difference(first color.RGBA, second color.RGBA) int {
+	largest := 0
+
+	for _, delta := range []int{
+		int(first.R) - int(second.R),
+		int(first.G) - int(second.G),
+		int(first.B) - int(second.B),
+	} {
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta > largest {
+			largest = delta
+		}
+	}
+
+	return largest
+}
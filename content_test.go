@@ -0,0 +1,109 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logo builds a graphic of flat colors, with a few hundred distinct colors of
+// antialiasing along the edges of a disc.
+func logo() *image.RGBA {
+	return testImage(64, 64, func(x int, y int) color.RGBA {
+		dx, dy := x-32, y-32
+		switch distance := dx*dx + dy*dy; {
+		case distance < 400:
+			return color.RGBA{200, 30, 40, 0xFF}
+		case distance < 600:
+			// Shade the antialiased ring by position, for many distinct colors
+			return color.RGBA{uint8(120 + x), uint8(100 + y), uint8(distance - 300), 0xFF}
+		default:
+			return white
+		}
+	})
+}
+
+func TestClassify(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.jpg"))
+	require.Nil(t, err)
+	defer file.Close()
+
+	photo, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	tests := []struct {
+		title   string
+		img     image.Image
+		content Content
+	}{
+		{
+			title:   "empty image",
+			img:     image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			content: Graphic,
+		},
+		{
+			title: "two colors",
+			img: testImage(20, 20, func(x int, _ int) color.RGBA {
+				if x < 10 {
+					return red
+				}
+				return blue
+			}),
+			content: Graphic,
+		},
+		{
+			title:   "antialiased logo",
+			img:     logo(),
+			content: Graphic,
+		},
+		{
+			title:   "photo",
+			img:     photo,
+			content: Photo,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			classification := Classify(test.img)
+			assert.Equal(t, test.content, classification.Content, "%+v", classification)
+
+		})
+	}
+
+	assert.Equal(t, "photo", Photo.String())
+	assert.Equal(t, "graphic", Graphic.String())
+
+}
+
+func TestAuto(t *testing.T) {
+
+	img := logo()
+
+	// Graphics keep their exact colors, most common first
+	assert.Equal(t, []color.RGBA{white, {200, 30, 40, 0xFF}}, Auto(img, 1))
+
+	// Graphics with fewer colors than requested are not padded
+	two := testImage(4, 1, func(x int, _ int) color.RGBA {
+		if x < 3 {
+			return red
+		}
+		return blue
+	})
+	assert.Equal(t, []color.RGBA{red, blue}, Auto(two, 3))
+
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// Rotate returns a copy of the given palette with the colors within [start,
+// end) rotated forward by the given number of steps, so that the color at
+// index start moves to index start+steps. Colors outside of the range are
+// unchanged. The range is clamped to the palette, and negative steps rotate
+// backward.
+func Rotate(colors []color.RGBA, start int, end int, steps int) []color.RGBA {
+	rotated := make([]color.RGBA, len(colors))
+	copy(rotated, colors)
+
+	if start < 0 {
+		start = 0
+	}
+	if end > len(colors) {
+		end = len(colors)
+	}
+
+	length := end - start
+	if length <= 0 {
+		return rotated
+	}
+
+	steps %= length
+	if steps < 0 {
+		steps += length
+	}
+
+	for index := 0; index < length; index++ {
+		rotated[start+(index+steps)%length] = colors[start+index]
+	}
+
+	return rotated
+}
+
+// Cycle returns one palette per frame of a classic palette cycling animation,
+// with the colors within [start, end) rotated forward by one more step each
+// frame. The first frame is the given palette.
+func Cycle(colors []color.RGBA, start int, end int, frames int) [][]color.RGBA {
+	if frames < 0 {
+		frames = 0
+	}
+
+	palettes := make([][]color.RGBA, frames)
+	for frame := range palettes {
+		palettes[frame] = Rotate(colors, start, end, frame)
+	}
+
+	return palettes
+}
+
+// Permute returns a copy of the given palette rearranged so that the color at
+// each index i is the color at index order[i] of the given palette. Indices in
+// the order outside of the palette are left as black.
+func Permute(colors []color.RGBA, order []int) []color.RGBA {
+	permuted := make([]color.RGBA, len(order))
+
+	for index, source := range order {
+		if source < 0 || source >= len(colors) {
+			permuted[index] = color.RGBA{0, 0, 0, 0xFF}
+			continue
+		}
+		permuted[index] = colors[source]
+	}
+
+	return permuted
+}
+
+// Recolor returns a paletted image sharing the pixels of the given image, but
+// with the given palette in place of its own, so that every frame of a palette
+// cycling animation needs no copy of the pixels.
+func Recolor(img *image.Paletted, colors []color.RGBA) *image.Paletted {
+	palette := make(color.Palette, len(colors))
+	for index, clr := range colors {
+		palette[index] = clr
+	}
+
+	return &image.Paletted{
+		Pix:     img.Pix,
+		Stride:  img.Stride,
+		Rect:    img.Rect,
+		Palette: palette,
+	}
+}
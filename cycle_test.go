@@ -0,0 +1,131 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 0xFF}
+	green = color.RGBA{0, 255, 0, 0xFF}
+	blue  = color.RGBA{0, 0, 255, 0xFF}
+	white = color.RGBA{255, 255, 255, 0xFF}
+)
+
+func TestRotate(t *testing.T) {
+
+	colors := []color.RGBA{red, green, blue, white}
+
+	tests := []struct {
+		title  string
+		start  int
+		end    int
+		steps  int
+		colors []color.RGBA
+	}{
+		{
+			title:  "whole palette",
+			start:  0,
+			end:    4,
+			steps:  1,
+			colors: []color.RGBA{white, red, green, blue},
+		},
+		{
+			title:  "range",
+			start:  1,
+			end:    3,
+			steps:  1,
+			colors: []color.RGBA{red, blue, green, white},
+		},
+		{
+			title:  "backward",
+			start:  0,
+			end:    4,
+			steps:  -1,
+			colors: []color.RGBA{green, blue, white, red},
+		},
+		{
+			title:  "full rotation",
+			start:  0,
+			end:    4,
+			steps:  8,
+			colors: []color.RGBA{red, green, blue, white},
+		},
+		{
+			title:  "clamped range",
+			start:  -2,
+			end:    10,
+			steps:  2,
+			colors: []color.RGBA{blue, white, red, green},
+		},
+		{
+			title:  "empty range",
+			start:  3,
+			end:    1,
+			steps:  1,
+			colors: []color.RGBA{red, green, blue, white},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.colors, Rotate(colors, test.start, test.end, test.steps))
+
+		})
+	}
+
+	// The given palette is never modified
+	assert.Equal(t, []color.RGBA{red, green, blue, white}, colors)
+
+}
+
+func TestCycle(t *testing.T) {
+
+	palettes := Cycle([]color.RGBA{red, green, blue}, 0, 3, 4)
+
+	assert.Equal(t, [][]color.RGBA{
+		{red, green, blue},
+		{blue, red, green},
+		{green, blue, red},
+		{red, green, blue},
+	}, palettes)
+
+	assert.Equal(t, [][]color.RGBA{}, Cycle([]color.RGBA{red}, 0, 1, -1))
+
+}
+
+func TestPermute(t *testing.T) {
+
+	colors := []color.RGBA{red, green, blue}
+
+	assert.Equal(t, []color.RGBA{blue, red, red, green}, Permute(colors, []int{2, 0, 0, 1}))
+	assert.Equal(t, []color.RGBA{{0, 0, 0, 0xFF}, green}, Permute(colors, []int{3, 1}))
+
+}
+
+func TestRecolor(t *testing.T) {
+
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), color.Palette{red, green})
+	img.SetColorIndex(1, 0, 1)
+
+	recolored := Recolor(img, []color.RGBA{blue, white})
+
+	assert.Equal(t, color.Color(blue), recolored.At(0, 0))
+	assert.Equal(t, color.Color(white), recolored.At(1, 0))
+
+	// The pixels are shared, and the original palette is unchanged
+	assert.True(t, &img.Pix[0] == &recolored.Pix[0])
+	assert.Equal(t, color.Color(red), img.At(0, 0))
+
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// differenceScale is the perceptual distance that is shown at full heat by
+// Difference. Larger distances are clamped.
+const differenceScale = 50
+
+// Difference takes in an image and a palette, and returns a heat map of the
+// error introduced by remapping every pixel to its nearest palette color. Each
+// pixel ranges from black where the colors match, through red and yellow, to
+// white where the perceptual distance reaches 50 or more, making it easy to
+// see where banding or color shifts occur. An empty palette leaves the heat
+// map black.
+func Difference(img image.Image, colors []color.RGBA) *image.RGBA {
+	rect := img.Bounds()
+	heatmap := image.NewRGBA(rect)
+
+	// Start with an opaque black heat map
+	for index := 3; index < len(heatmap.Pix); index += 4 {
+		heatmap.Pix[index] = 0xFF
+	}
+
+	if len(colors) == 0 {
+		return heatmap
+	}
+
+	// Photos repeat many colors, so each is only measured once
+	heats := map[color.RGBA]color.RGBA{}
+
+	// Pixels are extracted column by column, so their coordinates follow from
+	// their index alone
+	height := rect.Dy()
+	for index, pixel := range extract(nil, img, rect) {
+		clr, found := heats[pixel]
+		if !found {
+			assigned, _ := nearest(colors, pixel)
+			clr = heat(DeltaE(pixel, colors[assigned]) / differenceScale)
+			heats[pixel] = clr
+		}
+
+		heatmap.SetRGBA(rect.Min.X+index/height, rect.Min.Y+index%height, clr)
+	}
+
+	return heatmap
+}
+
+// heat returns the color of the given fraction along a ramp from black,
+// through red and yellow, to white. Fractions outside of [0, 1] are clamped.
+func heat(fraction float64) color.RGBA {
+	stops := []color.RGBA{
+		{0, 0, 0, 0xFF},
+		{255, 0, 0, 0xFF},
+		{255, 255, 0, 0xFF},
+		{255, 255, 255, 0xFF},
+	}
+
+	switch {
+	case fraction <= 0:
+		return stops[0]
+	case fraction >= 1:
+		return stops[len(stops)-1]
+	}
+
+	position := fraction * float64(len(stops)-1)
+	stop := int(position)
+
+	return Mix(stops[stop], stops[stop+1], position-float64(stop))
+}
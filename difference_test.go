@@ -0,0 +1,82 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDifference(t *testing.T) {
+
+	// The left column matches the palette exactly, and the right column is
+	// far from it
+	img := image.NewRGBA(image.Rect(10, 20, 12, 22))
+	img.SetRGBA(10, 20, color.RGBA{255, 0, 0, 0xFF})
+	img.SetRGBA(10, 21, color.RGBA{255, 0, 0, 0xFF})
+	img.SetRGBA(11, 20, color.RGBA{0, 255, 0, 0xFF})
+	img.SetRGBA(11, 21, color.RGBA{0, 255, 0, 0xFF})
+
+	var (
+		black = color.RGBA{0, 0, 0, 0xFF}
+		white = color.RGBA{255, 255, 255, 0xFF}
+	)
+
+	tests := []struct {
+		title  string
+		colors []color.RGBA
+		heats  []color.RGBA
+	}{
+		{
+			title:  "no colors",
+			colors: []color.RGBA{},
+			heats:  []color.RGBA{black, black, black, black},
+		},
+		{
+			title:  "exact colors",
+			colors: []color.RGBA{{255, 0, 0, 0xFF}, {0, 255, 0, 0xFF}},
+			heats:  []color.RGBA{black, black, black, black},
+		},
+		{
+			title:  "distant colors",
+			colors: []color.RGBA{{255, 0, 0, 0xFF}},
+			heats:  []color.RGBA{black, white, black, white},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			heatmap := Difference(img, test.colors)
+
+			assert.Equal(t, img.Bounds(), heatmap.Bounds())
+			assert.Equal(t, test.heats, []color.RGBA{
+				heatmap.RGBAAt(10, 20),
+				heatmap.RGBAAt(11, 20),
+				heatmap.RGBAAt(10, 21),
+				heatmap.RGBAAt(11, 21),
+			})
+
+		})
+	}
+
+}
+
+func TestHeat(t *testing.T) {
+
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xFF}, heat(-1))
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xFF}, heat(0))
+	assert.Equal(t, color.RGBA{255, 0, 0, 0xFF}, heat(1.0/3))
+	assert.Equal(t, color.RGBA{255, 255, 0, 0xFF}, heat(2.0/3))
+	assert.Equal(t, color.RGBA{255, 255, 255, 0xFF}, heat(1))
+	assert.Equal(t, color.RGBA{255, 255, 255, 0xFF}, heat(2))
+
+}
@@ -0,0 +1,39 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// DistinctColors counts the distinct RGB colors of the given image in a single
+// pass, stopping as soon as more than max have been seen. Returns the number of
+// distinct colors, counting no further than max, and whether every color was
+// counted. An image with at most max colors can be palettized losslessly, such
+// as with WithExhaustive, while one with more must be quantized.
+func DistinctColors(img image.Image, max int) (int, bool) {
+
+	if max < 0 {
+		max = 0
+	}
+
+	seen := make(map[color.RGBA]struct{})
+	rect := img.Bounds()
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+
+			r, g, b, _ := img.At(x, y).RGBA()
+			seen[color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xFF}] = struct{}{}
+
+			if len(seen) > max {
+				return max, false
+			}
+		}
+	}
+
+	return len(seen), true
+}
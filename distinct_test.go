@@ -0,0 +1,77 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinctColors(t *testing.T) {
+
+	stripes := testImage(8, 4, func(x int, _ int) color.RGBA {
+		return []color.RGBA{red, green, blue}[x%3]
+	})
+
+	tests := []struct {
+		title string
+		img   image.Image
+		max   int
+		count int
+		exact bool
+	}{
+		{
+			title: "empty image",
+			img:   image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			max:   4,
+			count: 0,
+			exact: true,
+		},
+		{
+			title: "fewer colors than the cap",
+			img:   stripes,
+			max:   4,
+			count: 3,
+			exact: true,
+		},
+		{
+			title: "exactly as many colors as the cap",
+			img:   stripes,
+			max:   3,
+			count: 3,
+			exact: true,
+		},
+		{
+			title: "more colors than the cap",
+			img:   stripes,
+			max:   2,
+			count: 2,
+			exact: false,
+		},
+		{
+			title: "negative cap",
+			img:   stripes,
+			max:   -1,
+			count: 0,
+			exact: false,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			count, exact := DistinctColors(test.img, test.max)
+			assert.Equal(t, test.count, count)
+			assert.Equal(t, test.exact, exact)
+
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// toneLevels is the number of levels an image is quantized to when choosing
+// the colors of its duotone or tritone.
+const toneLevels = 4
+
+// Tones takes in an image, and returns the colors for a duotone or tritone of
+// it, from shadows to highlights. Duotones use the darkest and lightest colors
+// of its palette, while tritones use the most saturated color as the midtone.
+// A number of tones outside of [2, 3] is clamped.
+func Tones(img image.Image, n int) []color.RGBA {
+	colors := Image(img, toneLevels)
+
+	darkest, lightest, saturated := 0, 0, 0
+	for index, clr := range colors {
+		if Luminance(clr) < Luminance(colors[darkest]) {
+			darkest = index
+		}
+		if Luminance(clr) > Luminance(colors[lightest]) {
+			lightest = index
+		}
+		if ToHSL(clr).S > ToHSL(colors[saturated]).S {
+			saturated = index
+		}
+	}
+
+	if n < 3 {
+		return []color.RGBA{colors[darkest], colors[lightest]}
+	}
+	return []color.RGBA{colors[darkest], colors[saturated], colors[lightest]}
+}
+
+// Duotone takes in an image and a list of colors from shadows to highlights,
+// such as those returned by Tones, and returns a copy of the image where every
+// pixel is blended smoothly between the colors by its lightness. A single
+// color fills the image, and no colors leave it black.
+func Duotone(img image.Image, stops []color.RGBA) *image.RGBA {
+	rect := img.Bounds()
+	toned := image.NewRGBA(rect)
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			lightness := ToLab(rgbaAt(img, x, y)).L / 100
+			toned.SetRGBA(x, y, tone(stops, lightness))
+		}
+	}
+
+	return toned
+}
+
+// tone returns the color the given fraction of the way along the gradient
+// through the given colors, which are evenly spaced.
+func tone(stops []color.RGBA, fraction float64) color.RGBA {
+	switch len(stops) {
+	case 0:
+		return color.RGBA{0, 0, 0, 0xFF}
+	case 1:
+		return stops[0]
+	}
+
+	position := math.Max(0, math.Min(1, fraction)) * float64(len(stops)-1)
+	segment := int(math.Min(position, float64(len(stops)-2)))
+
+	return Mix(stops[segment], stops[segment+1], position-float64(segment))
+}
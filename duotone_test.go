@@ -0,0 +1,124 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTones(t *testing.T) {
+
+	img := testImage(3, 1, func(x int, _ int) color.RGBA {
+		return []color.RGBA{
+			{20, 20, 30, 0xFF},
+			{200, 40, 40, 0xFF},
+			{240, 240, 230, 0xFF},
+		}[x]
+	})
+
+	tests := []struct {
+		n     int
+		tones []color.RGBA
+	}{
+		{
+			n: 2,
+			tones: []color.RGBA{
+				{20, 20, 30, 0xFF},
+				{240, 240, 230, 0xFF},
+			},
+		},
+		{
+			n: 3,
+			tones: []color.RGBA{
+				{20, 20, 30, 0xFF},
+				{200, 40, 40, 0xFF},
+				{240, 240, 230, 0xFF},
+			},
+		},
+		{
+			n: 1,
+			tones: []color.RGBA{
+				{20, 20, 30, 0xFF},
+				{240, 240, 230, 0xFF},
+			},
+		},
+		{
+			n: 5,
+			tones: []color.RGBA{
+				{20, 20, 30, 0xFF},
+				{200, 40, 40, 0xFF},
+				{240, 240, 230, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %d tones", index, test.n)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.tones, Tones(img, test.n))
+
+		})
+	}
+
+}
+
+func TestDuotone(t *testing.T) {
+
+	// Black, mid gray, and white
+	img := testImage(3, 1, func(x int, _ int) color.RGBA {
+		return []color.RGBA{
+			{0, 0, 0, 0xFF},
+			{119, 119, 119, 0xFF},
+			{255, 255, 255, 0xFF},
+		}[x]
+	})
+
+	tests := []struct {
+		title  string
+		stops  []color.RGBA
+		pixels []color.RGBA
+	}{
+		{
+			title:  "no colors",
+			stops:  []color.RGBA{},
+			pixels: []color.RGBA{{0, 0, 0, 0xFF}, {0, 0, 0, 0xFF}, {0, 0, 0, 0xFF}},
+		},
+		{
+			title:  "single color",
+			stops:  []color.RGBA{red},
+			pixels: []color.RGBA{red, red, red},
+		},
+		{
+			title:  "duotone",
+			stops:  []color.RGBA{blue, white},
+			pixels: []color.RGBA{blue, {128, 128, 255, 0xFF}, white},
+		},
+		{
+			title:  "tritone",
+			stops:  []color.RGBA{blue, red, white},
+			pixels: []color.RGBA{blue, red, white},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.pixels, pixelsOf(Duotone(img, test.stops)))
+
+		})
+	}
+
+	assert.Equal(t, image.Rect(0, 0, 0, 0), Duotone(image.NewRGBA(image.Rect(0, 0, 0, 0)), []color.RGBA{red}).Bounds())
+
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// EdgeConfig describes which strips along the edges of an image are sampled.
+type EdgeConfig struct {
+	// Top, Bottom, Left, & Right are the thickness of each strip, as a
+	// fraction of the image height (for top & bottom), or of the image width
+	// (for left & right). Edges with a thickness of zero are not sampled.
+	Top    float64
+	Bottom float64
+	Left   float64
+	Right  float64
+
+	// Segments is the number of equally sized pieces each strip is divided
+	// into, such as one per LED along that edge. Defaults to 1.
+	Segments int
+}
+
+// EdgePalettes holds the palettes extracted from each segment of each strip.
+// Top & bottom segments are ordered left to right, while left & right
+// segments are ordered top to bottom.
+type EdgePalettes struct {
+	Top    [][]color.RGBA
+	Bottom [][]color.RGBA
+	Left   [][]color.RGBA
+	Right  [][]color.RGBA
+}
+
+// Edges performs MMCQ on the strips along the edges of the given image, as
+// described by the given config. This is intended for ambient lighting, where
+// each LED displays the dominant color of the nearest part of the screen.
+func Edges(img image.Image, config EdgeConfig, levels int) EdgePalettes {
+
+	rect := img.Bounds()
+	segments := config.Segments
+	if segments <= 0 {
+		segments = 1
+	}
+
+	top := thickness(config.Top, rect.Dy())
+	bottom := thickness(config.Bottom, rect.Dy())
+	left := thickness(config.Left, rect.Dx())
+	right := thickness(config.Right, rect.Dx())
+
+	return EdgePalettes{
+		Top:    strip(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+top), segments, 1, levels),
+		Bottom: strip(img, image.Rect(rect.Min.X, rect.Max.Y-bottom, rect.Max.X, rect.Max.Y), segments, 1, levels),
+		Left:   strip(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+left, rect.Max.Y), 1, segments, levels),
+		Right:  strip(img, image.Rect(rect.Max.X-right, rect.Min.Y, rect.Max.X, rect.Max.Y), 1, segments, levels),
+	}
+}
+
+// thickness converts a fractional strip thickness into a number of pixels,
+// ensuring that any non-zero fraction covers at least one pixel.
+func thickness(fraction float64, size int) int {
+	if fraction <= 0 {
+		return 0
+	}
+
+	return int(math.Min(float64(size), math.Max(1, math.Round(fraction*float64(size)))))
+}
+
+// strip performs MMCQ on each segment of the given strip, or returns nil if
+// the strip is empty.
+func strip(img image.Image, rect image.Rectangle, cols int, rows int, levels int) [][]color.RGBA {
+
+	if rect.Empty() {
+		return nil
+	}
+
+	return grid(img, rect, cols, rows, levels)
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdges(t *testing.T) {
+
+	var (
+		red   = color.RGBA{255, 0, 0, 0xFF}
+		green = color.RGBA{0, 255, 0, 0xFF}
+		blue  = color.RGBA{0, 0, 255, 0xFF}
+		white = color.RGBA{255, 255, 255, 0xFF}
+		black = color.RGBA{0, 0, 0, 0xFF}
+	)
+
+	// A 10x10 black image with a colored 1 pixel border. The top row is red
+	// on the left half and green on the right half, the bottom row is blue,
+	// and the left & right columns are white.
+	framed := testImage(10, 10, func(x int, y int) color.RGBA {
+		switch {
+		case y == 0 && x < 5:
+			return red
+		case y == 0:
+			return green
+		case y == 9:
+			return blue
+		case x == 0 || x == 9:
+			return white
+		default:
+			return black
+		}
+	})
+
+	tests := []struct {
+		title    string
+		config   EdgeConfig
+		palettes EdgePalettes
+	}{
+		{
+			title:    "no edges",
+			config:   EdgeConfig{},
+			palettes: EdgePalettes{},
+		},
+		{
+			title: "top edge only",
+			config: EdgeConfig{
+				Top: 0.1,
+			},
+			palettes: EdgePalettes{
+				Top: [][]color.RGBA{
+					{{128, 128, 0, 0xFF}},
+				},
+			},
+		},
+		{
+			title: "segmented top edge",
+			config: EdgeConfig{
+				Top:      0.1,
+				Segments: 2,
+			},
+			palettes: EdgePalettes{
+				Top: [][]color.RGBA{
+					{red},
+					{green},
+				},
+			},
+		},
+		{
+			title: "tiny thickness covers a pixel",
+			config: EdgeConfig{
+				Bottom: 0.001,
+			},
+			palettes: EdgePalettes{
+				Bottom: [][]color.RGBA{
+					{blue},
+				},
+			},
+		},
+		{
+			title: "segmented sides",
+			config: EdgeConfig{
+				Left:     0.1,
+				Right:    0.1,
+				Segments: 5,
+			},
+			palettes: EdgePalettes{
+				Left: [][]color.RGBA{
+					{{255, 128, 128, 0xFF}},
+					{white},
+					{white},
+					{white},
+					{{128, 128, 255, 0xFF}},
+				},
+				Right: [][]color.RGBA{
+					{{128, 255, 128, 0xFF}},
+					{white},
+					{white},
+					{white},
+					{{128, 128, 255, 0xFF}},
+				},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palettes := Edges(framed, test.config, 0)
+
+			assert.Equal(t, test.palettes, palettes)
+
+		})
+	}
+
+}
@@ -0,0 +1,139 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeJPEG encodes a solid image of the given size & color as a JPEG.
+func encodeJPEG(t *testing.T, width int, height int, clr color.RGBA) []byte {
+	img := testImage(width, height, func(int, int) color.RGBA { return clr })
+
+	var buf bytes.Buffer
+	require.Nil(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// withEXIF inserts an APP1 segment holding EXIF metadata with the given
+// thumbnail, in the given byte order, directly after the start of image
+// marker of the given JPEG.
+func withEXIF(data []byte, thumbnail []byte, order binary.ByteOrder) []byte {
+	var tiff bytes.Buffer
+
+	if order == binary.LittleEndian {
+		tiff.WriteString("II*\x00")
+	} else {
+		tiff.WriteString("MM\x00*")
+	}
+
+	// An empty first directory, followed by the second directory holding
+	// the location of the thumbnail, and then the thumbnail itself
+	binary.Write(&tiff, order, uint32(8))
+	binary.Write(&tiff, order, uint16(0))
+	binary.Write(&tiff, order, uint32(14))
+	binary.Write(&tiff, order, uint16(2))
+	binary.Write(&tiff, order, []uint16{exifThumbnailOffset, 4})
+	binary.Write(&tiff, order, []uint32{1, 44})
+	binary.Write(&tiff, order, []uint16{exifThumbnailLength, 4})
+	binary.Write(&tiff, order, []uint32{1, uint32(len(thumbnail))})
+	binary.Write(&tiff, order, uint32(0))
+	tiff.Write(thumbnail)
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var buf bytes.Buffer
+	buf.Write(data[:2])
+	buf.Write([]byte{0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(len(segment)+2))
+	buf.Write(segment)
+	buf.Write(data[2:])
+
+	return buf.Bytes()
+}
+
+func TestEXIFThumbnail(t *testing.T) {
+
+	full := encodeJPEG(t, 64, 64, color.RGBA{200, 30, 30, 0xFF})
+	thumbnail := encodeJPEG(t, 8, 8, color.RGBA{30, 30, 200, 0xFF})
+
+	tests := []struct {
+		title     string
+		data      []byte
+		thumbnail bool
+	}{
+		{
+			title: "without metadata",
+			data:  full,
+		},
+		{
+			title:     "little endian metadata",
+			data:      withEXIF(full, thumbnail, binary.LittleEndian),
+			thumbnail: true,
+		},
+		{
+			title:     "big endian metadata",
+			data:      withEXIF(full, thumbnail, binary.BigEndian),
+			thumbnail: true,
+		},
+		{
+			title: "truncated metadata",
+			data:  withEXIF(full, thumbnail, binary.LittleEndian)[:40],
+		},
+		{
+			title: "not a jpeg",
+			data:  []byte("GIF89a"),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			found, ok := exifThumbnail(test.data)
+			assert.Equal(t, test.thumbnail, ok)
+			if test.thumbnail {
+				assert.Equal(t, thumbnail, found)
+			}
+
+		})
+	}
+
+	// The metadata does not stop the full image from being decoded
+	data := withEXIF(full, thumbnail, binary.LittleEndian)
+	_, err := jpeg.Decode(bytes.NewReader(data))
+	require.Nil(t, err)
+
+	// Only the thumbnail is quantized with the option
+	colors, err := NewQuantizer(WithEXIFThumbnail()).Bytes(data, 0)
+	require.Nil(t, err)
+	assert.True(t, colors[0].B > colors[0].R)
+
+	colors, err = NewQuantizer().Bytes(data, 0)
+	require.Nil(t, err)
+	assert.True(t, colors[0].R > colors[0].B)
+
+	// Images without a thumbnail are decoded as usual
+	colors, err = NewQuantizer(WithEXIFThumbnail()).Bytes(full, 0)
+	require.Nil(t, err)
+	assert.True(t, colors[0].R > colors[0].B)
+
+	// Thumbnails are bound by the decode limits too, but need not be as large
+	// as the full image
+	_, err = NewQuantizer(WithEXIFThumbnail(), WithDecodeLimits(16, 16, 0)).Bytes(data, 0)
+	assert.Nil(t, err)
+
+	_, err = NewQuantizer(WithEXIFThumbnail(), WithDecodeLimits(4, 4, 0)).Bytes(data, 0)
+	assert.Equal(t, ErrImageTooLarge, err)
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image/color"
+	"sort"
+	"strings"
+)
+
+// CSSGradient takes in a slice of RGB colors, and returns a CSS
+// linear-gradient running from the darkest color at the top to the lightest
+// color at the bottom.
+func CSSGradient(colors []color.RGBA) string {
+
+	sorted := make([]color.RGBA, len(colors))
+	copy(sorted, colors)
+
+	// Order colors from darkest to lightest
+	sort.SliceStable(sorted, func(i int, j int) bool {
+		return Luminance(sorted[i]) < Luminance(sorted[j])
+	})
+
+	stops := make([]string, len(sorted))
+	for index, clr := range sorted {
+		stops[index] = Hex(clr)
+	}
+
+	// A gradient requires at least two color stops
+	if len(stops) == 1 {
+		stops = append(stops, stops[0])
+	}
+
+	return "linear-gradient(" + strings.Join(stops, ", ") + ")"
+}
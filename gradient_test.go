@@ -0,0 +1,60 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSSGradient(t *testing.T) {
+
+	tests := []struct {
+		title    string
+		colors   []color.RGBA
+		gradient string
+	}{
+		{
+			title:    "no colors",
+			colors:   []color.RGBA{},
+			gradient: "linear-gradient()",
+		},
+		{
+			title: "single color",
+			colors: []color.RGBA{
+				{0x13, 0x25, 0x5c, 0xFF},
+			},
+			gradient: "linear-gradient(#13255C, #13255C)",
+		},
+		{
+			title: "sorted by luminance",
+			colors: []color.RGBA{
+				{255, 255, 255, 0xFF},
+				{0, 0, 255, 0xFF},
+				{0, 255, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{0, 0, 0, 0xFF},
+			},
+			gradient: "linear-gradient(#000000, #0000FF, #FF0000, #00FF00, #FFFFFF)",
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			original := append([]color.RGBA{}, test.colors...)
+
+			assert.Equal(t, test.gradient, CSSGradient(test.colors))
+			assert.Equal(t, original, test.colors)
+
+		})
+	}
+
+}
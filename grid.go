@@ -0,0 +1,88 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// Grid divides the given image into a grid of cols by rows tiles, and performs
+// MMCQ on each tile independently. Returns a slice of palettes in row-major
+// order, so the palette for a given tile is at index row*cols+col.
+func Grid(img image.Image, cols int, rows int, levels int) [][]color.RGBA {
+
+	if cols <= 0 || rows <= 0 {
+		return [][]color.RGBA{}
+	}
+
+	return grid(img, img.Bounds(), cols, rows, levels)
+}
+
+// grid performs MMCQ on each tile of a grid laid over the given rectangle.
+func grid(img image.Image, rect image.Rectangle, cols int, rows int, levels int) [][]color.RGBA {
+
+	var quantizer Quantizer
+	levels = clampLevels(levels)
+	palettes := make([][]color.RGBA, 0, cols*rows)
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			region := tile(rect, cols, rows, col, row)
+			palettes = append(palettes, quantizer.region(img, region, levels))
+		}
+	}
+
+	return palettes
+}
+
+// tile returns the bounds of a single tile within a grid laid over the given
+// rectangle. Tiles are sized as evenly as possible, and together they cover
+// the entire rectangle.
+func tile(rect image.Rectangle, cols int, rows int, col int, row int) image.Rectangle {
+	width, height := rect.Dx(), rect.Dy()
+
+	return image.Rect(
+		rect.Min.X+col*width/cols,
+		rect.Min.Y+row*height/rows,
+		rect.Min.X+(col+1)*width/cols,
+		rect.Min.Y+(row+1)*height/rows,
+	)
+}
+
+// Downsample box filters the given image so that neither dimension exceeds the
+// given limit, preserving its aspect ratio. Every pixel of the result is the
+// alpha weighted average of the tile of the image that it covers, as a
+// non-premultiplied color. Images that already fit keep their original size.
+func Downsample(img image.Image, limit int) *image.NRGBA {
+
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	if limit <= 0 || width <= 0 || height <= 0 {
+		return image.NewNRGBA(image.Rectangle{})
+	}
+
+	// Scale the largest dimension down to the limit
+	cols, rows := width, height
+	switch {
+	case width > limit && width >= height:
+		cols, rows = limit, maxInt(1, height*limit/width)
+	case height > limit:
+		cols, rows = maxInt(1, width*limit/height), limit
+	}
+
+	small := image.NewNRGBA(image.Rect(0, 0, cols, rows))
+	var pixels []color.RGBA
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			pixels = extractNRGBA(pixels, img, tile(rect, cols, rows, col, row))
+			small.SetNRGBA(col, row, box{pixels: pixels}.averageNRGBA(false))
+		}
+	}
+
+	return small
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid(t *testing.T) {
+
+	// A 4x2 image with a distinct solid color in each 2x1 quadrant
+	quadrants := testImage(4, 2, func(x int, y int) color.RGBA {
+		switch {
+		case x < 2 && y < 1:
+			return color.RGBA{255, 0, 0, 0xFF}
+		case x >= 2 && y < 1:
+			return color.RGBA{0, 255, 0, 0xFF}
+		case x < 2:
+			return color.RGBA{0, 0, 255, 0xFF}
+		default:
+			return color.RGBA{255, 255, 255, 0xFF}
+		}
+	})
+
+	tests := []struct {
+		title    string
+		img      image.Image
+		cols     int
+		rows     int
+		levels   int
+		palettes [][]color.RGBA
+	}{
+		{
+			title:    "no tiles",
+			img:      quadrants,
+			cols:     0,
+			rows:     2,
+			levels:   0,
+			palettes: [][]color.RGBA{},
+		},
+		{
+			title:  "single tile",
+			img:    quadrants,
+			cols:   1,
+			rows:   1,
+			levels: 0,
+			palettes: [][]color.RGBA{
+				{{128, 128, 128, 0xFF}},
+			},
+		},
+		{
+			title:  "quadrant tiles",
+			img:    quadrants,
+			cols:   2,
+			rows:   2,
+			levels: 0,
+			palettes: [][]color.RGBA{
+				{{255, 0, 0, 0xFF}},
+				{{0, 255, 0, 0xFF}},
+				{{0, 0, 255, 0xFF}},
+				{{255, 255, 255, 0xFF}},
+			},
+		},
+		{
+			title:  "offset bounds",
+			img:    quadrants.SubImage(image.Rect(2, 0, 4, 2)),
+			cols:   1,
+			rows:   2,
+			levels: 0,
+			palettes: [][]color.RGBA{
+				{{0, 255, 0, 0xFF}},
+				{{255, 255, 255, 0xFF}},
+			},
+		},
+		{
+			title:  "more tiles than pixels",
+			img:    quadrants,
+			cols:   8,
+			rows:   1,
+			levels: 0,
+			palettes: [][]color.RGBA{
+				{{0, 0, 0, 0xFF}},
+				{{128, 0, 128, 0xFF}},
+				{{0, 0, 0, 0xFF}},
+				{{128, 0, 128, 0xFF}},
+				{{0, 0, 0, 0xFF}},
+				{{128, 255, 128, 0xFF}},
+				{{0, 0, 0, 0xFF}},
+				{{128, 255, 128, 0xFF}},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palettes := Grid(test.img, test.cols, test.rows, test.levels)
+
+			assert.Equal(t, test.palettes, palettes)
+
+		})
+	}
+
+}
+
+func TestDownsample(t *testing.T) {
+
+	// A 4x2 image that is red on the left, and half transparent blue on the
+	// right
+	halves := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x < 2 {
+				halves.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 0xFF})
+			} else {
+				halves.SetNRGBA(x, y, color.NRGBA{0, 0, 255, 0x80})
+			}
+		}
+	}
+
+	tests := []struct {
+		title  string
+		img    image.Image
+		limit  int
+		pixels [][]color.NRGBA
+	}{
+		{
+			title: "no limit",
+			img:   halves,
+			limit: 0,
+		},
+		{
+			title: "already fits",
+			img:   halves,
+			limit: 4,
+			pixels: [][]color.NRGBA{
+				{{255, 0, 0, 0xFF}, {255, 0, 0, 0xFF}, {0, 0, 255, 0x80}, {0, 0, 255, 0x80}},
+				{{255, 0, 0, 0xFF}, {255, 0, 0, 0xFF}, {0, 0, 255, 0x80}, {0, 0, 255, 0x80}},
+			},
+		},
+		{
+			title: "landscape",
+			img:   halves,
+			limit: 2,
+			pixels: [][]color.NRGBA{
+				{{255, 0, 0, 0xFF}, {0, 0, 255, 0x80}},
+			},
+		},
+		{
+			title: "alpha weighted",
+			img:   halves,
+			limit: 1,
+			pixels: [][]color.NRGBA{
+				{{170, 0, 85, 0xC0}},
+			},
+		},
+		{
+			title: "offset bounds",
+			img:   halves.SubImage(image.Rect(1, 0, 3, 2)),
+			limit: 1,
+			pixels: [][]color.NRGBA{
+				{{170, 0, 85, 0xC0}},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			small := Downsample(test.img, test.limit)
+
+			var pixels [][]color.NRGBA
+			for y := 0; y < small.Rect.Dy(); y++ {
+				row := make([]color.NRGBA, small.Rect.Dx())
+				for x := range row {
+					row[x] = small.NRGBAAt(x, y)
+				}
+				pixels = append(pixels, row)
+			}
+
+			assert.Equal(t, test.pixels, pixels)
+
+		})
+	}
+
+}
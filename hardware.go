@@ -0,0 +1,82 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import "image/color"
+
+// Format is a hardware color format with fewer than 8 bits per component,
+// as used by embedded displays & retro consoles.
+type Format int
+
+const (
+	// RGB565 has 5 bits of red, 6 bits of green, and 5 bits of blue, as used
+	// by most small TFT displays.
+	RGB565 Format = iota + 1
+
+	// RGB555 has 5 bits of each component, as used by the Game Boy Advance &
+	// Super Nintendo.
+	RGB555
+
+	// RGB444 has 4 bits of each component, as used by the Amiga OCS.
+	RGB444
+)
+
+// bits returns the number of bits of the red, green, & blue components.
+func (f Format) bits() (uint, uint, uint) {
+	switch f {
+	case RGB565:
+		return 5, 6, 5
+	case RGB555:
+		return 5, 5, 5
+	case RGB444:
+		return 4, 4, 4
+	default:
+		return 8, 8, 8
+	}
+}
+
+// WithHardwareFormat snaps every palette color to the nearest color that the
+// given hardware format can represent. Colors that snap to the same value are
+// merged when the Quantizer was created with WithAllowShortPalette. Applies to
+// Image, Colors, Pixels, RGBBuffer, and Raw.
+func WithHardwareFormat(format Format) Option {
+	return func(q *Quantizer) {
+		q.format = format
+	}
+}
+
+// Snap takes in a palette, and returns a new palette of every color snapped to
+// the nearest color that the given hardware format can represent, expressed
+// with 8 bits per component as the hardware would display it. Unknown formats
+// leave the colors unchanged.
+func Snap(colors []color.RGBA, format Format) []color.RGBA {
+	r, g, b := format.bits()
+	snapped := make([]color.RGBA, len(colors))
+
+	for index, clr := range colors {
+		snapped[index] = color.RGBA{
+			snapComponent(clr.R, r),
+			snapComponent(clr.G, g),
+			snapComponent(clr.B, b),
+			clr.A,
+		}
+	}
+
+	return snapped
+}
+
+// snapComponent rounds the given component to the nearest value with the
+// given number of bits, and then expands it back to 8 bits by replicating its
+// high bits, so that the extremes remain 0 & 255.
+func snapComponent(component uint8, bits uint) uint8 {
+	if bits >= 8 {
+		return component
+	}
+
+	levels := uint32(1)<<bits - 1
+	value := (uint32(component)*levels + 127) / 255
+
+	return uint8(value<<(8-bits) | value>>(2*bits-8))
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnap(t *testing.T) {
+
+	colors := []color.RGBA{
+		{0x00, 0x00, 0x00, 0xFF},
+		{0xFF, 0xFF, 0xFF, 0xFF},
+		{100, 150, 200, 0x80},
+	}
+
+	tests := []struct {
+		title    string
+		format   Format
+		expected []color.RGBA
+	}{
+		{
+			title:  "rgb565",
+			format: RGB565,
+			expected: []color.RGBA{
+				{0x00, 0x00, 0x00, 0xFF},
+				{0xFF, 0xFF, 0xFF, 0xFF},
+				{99, 150, 198, 0x80},
+			},
+		},
+		{
+			title:  "rgb555",
+			format: RGB555,
+			expected: []color.RGBA{
+				{0x00, 0x00, 0x00, 0xFF},
+				{0xFF, 0xFF, 0xFF, 0xFF},
+				{99, 148, 198, 0x80},
+			},
+		},
+		{
+			title:  "rgb444",
+			format: RGB444,
+			expected: []color.RGBA{
+				{0x00, 0x00, 0x00, 0xFF},
+				{0xFF, 0xFF, 0xFF, 0xFF},
+				{102, 153, 204, 0x80},
+			},
+		},
+		{
+			title:    "unknown format",
+			format:   0,
+			expected: colors,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Snap(colors, test.format))
+		})
+	}
+}
+
+func TestSnapIdempotent(t *testing.T) {
+
+	for _, format := range []Format{RGB565, RGB555, RGB444} {
+		for component := 0; component < 256; component++ {
+			clr := color.RGBA{uint8(component), uint8(component), uint8(component), 0xFF}
+			snapped := Snap([]color.RGBA{clr}, format)
+
+			require.Equal(t, snapped, Snap(snapped, format))
+		}
+	}
+}
+
+func TestWithHardwareFormat(t *testing.T) {
+
+	img := testImage(4, 4, func(x int, _ int) color.RGBA {
+		return []color.RGBA{{100, 150, 200, 0xFF}, {101, 151, 201, 0xFF}}[x%2]
+	})
+
+	tests := []struct {
+		title    string
+		options  []Option
+		expected []color.RGBA
+	}{
+		{
+			title:   "padded palette",
+			options: []Option{WithHardwareFormat(RGB444)},
+			expected: []color.RGBA{
+				{102, 153, 204, 0xFF},
+				{102, 153, 204, 0xFF},
+			},
+		},
+		{
+			title:   "snapped colors are merged",
+			options: []Option{WithHardwareFormat(RGB444), WithAllowShortPalette()},
+			expected: []color.RGBA{
+				{102, 153, 204, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+			palette, err := NewQuantizer(test.options...).Image(img, 1)
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, palette)
+		})
+	}
+}
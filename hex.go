@@ -0,0 +1,64 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+)
+
+// Hex takes in an RGB color, and returns its "#RRGGBB" hex representation.
+func Hex(clr color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", clr.R, clr.G, clr.B)
+}
+
+// HexAlpha takes in an RGBA color, and returns its "#RRGGBBAA" hex
+// representation.
+func HexAlpha(clr color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X%02X", clr.R, clr.G, clr.B, clr.A)
+}
+
+// ParseHex takes in a hex color string, and returns the color it represents.
+// The short "#RGB" & "#RGBA" forms, and the long "#RRGGBB" & "#RRGGBBAA" forms
+// are all accepted. Colors without an alpha component are fully opaque.
+func ParseHex(str string) (color.RGBA, error) {
+
+	if len(str) == 0 || str[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", str)
+	}
+
+	digits := str[1:]
+
+	// Expand the short forms by doubling each digit
+	if len(digits) == 3 || len(digits) == 4 {
+		expanded := make([]byte, 0, len(digits)*2)
+		for index := 0; index < len(digits); index++ {
+			expanded = append(expanded, digits[index], digits[index])
+		}
+		digits = string(expanded)
+	}
+
+	// Colors without an alpha component are fully opaque
+	if len(digits) == 6 {
+		digits += "FF"
+	}
+
+	if len(digits) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", str)
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", str)
+	}
+
+	return color.RGBA{
+		uint8(value >> 24),
+		uint8(value >> 16),
+		uint8(value >> 8),
+		uint8(value),
+	}, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHex(t *testing.T) {
+
+	tests := []struct {
+		title string
+		color color.RGBA
+		hex   string
+		alpha string
+	}{
+		{
+			title: "black",
+			color: color.RGBA{0, 0, 0, 0xFF},
+			hex:   "#000000",
+			alpha: "#000000FF",
+		},
+		{
+			title: "white",
+			color: color.RGBA{255, 255, 255, 0xFF},
+			hex:   "#FFFFFF",
+			alpha: "#FFFFFFFF",
+		},
+		{
+			title: "translucent",
+			color: color.RGBA{0x1a, 0x2b, 0x3c, 0x80},
+			hex:   "#1A2B3C",
+			alpha: "#1A2B3C80",
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.hex, Hex(test.color))
+			assert.Equal(t, test.alpha, HexAlpha(test.color))
+
+		})
+	}
+
+}
+
+func TestParseHex(t *testing.T) {
+
+	tests := []struct {
+		title string
+		hex   string
+		color color.RGBA
+		valid bool
+	}{
+		{
+			title: "long form",
+			hex:   "#1a2b3c",
+			color: color.RGBA{0x1a, 0x2b, 0x3c, 0xFF},
+			valid: true,
+		},
+		{
+			title: "long form uppercase",
+			hex:   "#1A2B3C",
+			color: color.RGBA{0x1a, 0x2b, 0x3c, 0xFF},
+			valid: true,
+		},
+		{
+			title: "long form alpha",
+			hex:   "#1a2b3c80",
+			color: color.RGBA{0x1a, 0x2b, 0x3c, 0x80},
+			valid: true,
+		},
+		{
+			title: "short form",
+			hex:   "#abc",
+			color: color.RGBA{0xaa, 0xbb, 0xcc, 0xFF},
+			valid: true,
+		},
+		{
+			title: "short form alpha",
+			hex:   "#abc8",
+			color: color.RGBA{0xaa, 0xbb, 0xcc, 0x88},
+			valid: true,
+		},
+		{
+			title: "empty string",
+			hex:   "",
+		},
+		{
+			title: "missing prefix",
+			hex:   "1a2b3c",
+		},
+		{
+			title: "bad length",
+			hex:   "#1a2b3",
+		},
+		{
+			title: "bad digits",
+			hex:   "#1a2b3g",
+		},
+		{
+			title: "signed digits",
+			hex:   "#+a2b3c",
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			clr, err := ParseHex(test.hex)
+
+			if !test.valid {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.color, clr)
+
+		})
+	}
+
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"math"
+)
+
+// MaxBins is the largest number of bins per channel of a histogram, one for
+// every 8-bit component value.
+const MaxBins = 256
+
+// Distribution holds per-channel histograms of the pixels in an image.
+type Distribution struct {
+	// Bins is the number of bins in each histogram.
+	Bins int `json:"bins" yaml:"bins"`
+
+	// Pixels is the number of pixels counted in each histogram.
+	Pixels int `json:"pixels" yaml:"pixels"`
+
+	// RGB holds the histograms of the red, green, & blue components, where
+	// each bin covers an equal range of component values.
+	RGB [3][]int `json:"rgb" yaml:"rgb"`
+
+	// HSV holds the histograms of the hue, saturation, & value components,
+	// where each bin covers an equal range of degrees, or of [0, 1].
+	HSV [3][]int `json:"hsv" yaml:"hsv"`
+}
+
+// Histogram takes in an image, and returns the histograms of its RGB and HSV
+// components, with the given number of bins per channel, computed in a single
+// pass. A number of bins outside of [1, MaxBins] is clamped.
+func Histogram(img image.Image, bins int) Distribution {
+	switch {
+	case bins < 1:
+		bins = 1
+	case bins > MaxBins:
+		bins = MaxBins
+	}
+
+	distribution := Distribution{Bins: bins}
+	for channel := range distribution.RGB {
+		distribution.RGB[channel] = make([]int, bins)
+		distribution.HSV[channel] = make([]int, bins)
+	}
+
+	pixels := extract(nil, img, img.Bounds())
+
+	for _, pixel := range pixels {
+		distribution.RGB[0][int(pixel.R)*bins/256]++
+		distribution.RGB[1][int(pixel.G)*bins/256]++
+		distribution.RGB[2][int(pixel.B)*bins/256]++
+
+		hsv := ToHSV(pixel)
+		distribution.HSV[0][bin(hsv.H/360, bins)]++
+		distribution.HSV[1][bin(hsv.S, bins)]++
+		distribution.HSV[2][bin(hsv.V, bins)]++
+	}
+
+	distribution.Pixels = len(pixels)
+
+	return distribution
+}
+
+// bin returns the bin holding the given fraction within [0, 1], where the
+// last bin also holds a fraction of exactly 1.
+func bin(fraction float64, bins int) int {
+	return int(math.Min(math.Floor(fraction*float64(bins)), float64(bins-1)))
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+
+	tests := []struct {
+		title        string
+		img          image.Image
+		bins         int
+		distribution Distribution
+	}{
+		{
+			title: "empty image",
+			img:   image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			bins:  2,
+			distribution: Distribution{
+				Bins: 2,
+				RGB:  [3][]int{{0, 0}, {0, 0}, {0, 0}},
+				HSV:  [3][]int{{0, 0}, {0, 0}, {0, 0}},
+			},
+		},
+		{
+			title: "black and white",
+			img: testImage(2, 2, func(x int, _ int) color.RGBA {
+				if x < 1 {
+					return color.RGBA{0, 0, 0, 0xFF}
+				}
+				return color.RGBA{255, 255, 255, 0xFF}
+			}),
+			bins: 4,
+			distribution: Distribution{
+				Bins:   4,
+				Pixels: 4,
+				RGB:    [3][]int{{2, 0, 0, 2}, {2, 0, 0, 2}, {2, 0, 0, 2}},
+				HSV:    [3][]int{{4, 0, 0, 0}, {4, 0, 0, 0}, {2, 0, 0, 2}},
+			},
+		},
+		{
+			title: "primary colors",
+			img: testImage(3, 1, func(x int, _ int) color.RGBA {
+				return []color.RGBA{
+					{255, 0, 0, 0xFF},
+					{0, 255, 0, 0xFF},
+					{0, 0, 128, 0xFF},
+				}[x]
+			}),
+			bins: 3,
+			distribution: Distribution{
+				Bins:   3,
+				Pixels: 3,
+				RGB:    [3][]int{{2, 0, 1}, {2, 0, 1}, {2, 1, 0}},
+				HSV:    [3][]int{{1, 1, 1}, {0, 0, 3}, {0, 1, 2}},
+			},
+		},
+		{
+			title: "clamped bins",
+			img: testImage(1, 1, func(_ int, _ int) color.RGBA {
+				return color.RGBA{10, 20, 30, 0xFF}
+			}),
+			bins: 0,
+			distribution: Distribution{
+				Bins:   1,
+				Pixels: 1,
+				RGB:    [3][]int{{1}, {1}, {1}},
+				HSV:    [3][]int{{1}, {1}, {1}},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.distribution, Histogram(test.img, test.bins))
+
+		})
+	}
+
+	distribution := Histogram(testImage(1, 1, func(_ int, _ int) color.RGBA {
+		return color.RGBA{0, 0, 0, 0xFF}
+	}), 1000)
+	assert.Equal(t, MaxBins, distribution.Bins)
+	assert.Equal(t, MaxBins, len(distribution.RGB[0]))
+
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package imagehash computes perceptual hashes, which are the usual companion
+// to a palette in image deduplication and similarity pipelines. Visually
+// similar images have hashes that differ in only a few bits.
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/joshdk/quantize"
+)
+
+// Fingerprint holds the palette and perceptual hashes of an image.
+type Fingerprint struct {
+	Palette []color.RGBA `json:"palette"`
+	DHash   uint64       `json:"dhash"`
+	PHash   uint64       `json:"phash"`
+}
+
+// NewFingerprint takes in an image, and returns its palette to the given
+// number of levels alongside its perceptual hashes, so that the image only
+// needs to be decoded once.
+func NewFingerprint(img image.Image, levels int) Fingerprint {
+	return Fingerprint{
+		Palette: quantize.Image(img, levels),
+		DHash:   DHash(img),
+		PHash:   PHash(img),
+	}
+}
+
+// DHash takes in an image, and returns its 64-bit difference hash. The image
+// is reduced to 9x8 grayscale pixels, and each bit records whether a pixel is
+// darker than its neighbor to the right.
+func DHash(img image.Image) uint64 {
+	const width, height = 9, 8
+
+	gray := grayscale(img, width, height)
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if gray[y*width+x] < gray[y*width+x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// PHash takes in an image, and returns its 64-bit perceptual hash. The image
+// is reduced to 32x32 grayscale pixels, and each bit records whether one of
+// the 8x8 lowest frequency DCT coefficients is above their median.
+func PHash(img image.Image) uint64 {
+	const size, low = 32, 8
+
+	gray := grayscale(img, size, size)
+
+	// Only the lowest frequencies of the 2D DCT-II are needed
+	coefficients := make([]float64, 0, low*low)
+	for v := 0; v < low; v++ {
+		for u := 0; u < low; u++ {
+			var sum float64
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += gray[y*size+x] *
+						math.Cos(math.Pi*float64(u)*(float64(x)+0.5)/size) *
+						math.Cos(math.Pi*float64(v)*(float64(y)+0.5)/size)
+				}
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+
+	sorted := append([]float64{}, coefficients...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for _, coefficient := range coefficients {
+		hash <<= 1
+		if coefficient > median {
+			hash |= 1
+		}
+	}
+
+	return hash
+}
+
+// Distance returns the number of bits that differ between two hashes.
+func Distance(first uint64, second uint64) int {
+	return bits.OnesCount64(first ^ second)
+}
+
+// grayscale reduces the given image to the given dimensions by averaging the
+// luma of the pixels covered by each reduced pixel, returned in row-major
+// order.
+func grayscale(img image.Image, width int, height int) []float64 {
+	rect := img.Bounds()
+	gray := make([]float64, width*height)
+
+	if rect.Empty() {
+		return gray
+	}
+
+	for y := 0; y < height; y++ {
+		top, bottom := span(rect.Min.Y, rect.Dy(), y, height)
+
+		for x := 0; x < width; x++ {
+			left, right := span(rect.Min.X, rect.Dx(), x, width)
+
+			var sum float64
+			for sy := top; sy < bottom; sy++ {
+				for sx := left; sx < right; sx++ {
+					r, g, b, _ := img.At(sx, sy).RGBA()
+					sum += 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+				}
+			}
+
+			gray[y*width+x] = sum / float64((bottom-top)*(right-left))
+		}
+	}
+
+	return gray
+}
+
+// span returns the range of source coordinates covered by the given reduced
+// coordinate, which always covers at least one source coordinate.
+func span(origin int, length int, index int, count int) (int, int) {
+	start := origin + index*length/count
+	end := origin + (index+1)*length/count
+
+	if end <= start {
+		end = start + 1
+	}
+
+	return start, end
+}
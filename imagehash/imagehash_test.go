@@ -0,0 +1,206 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/joshdk/quantize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// build builds an image of the given size, colored by the given function.
+func build(width int, height int, fn func(x int, y int) color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, fn(x, y))
+		}
+	}
+
+	return img
+}
+
+// load decodes the named image from the shared testdata directory.
+func load(t *testing.T, name string) image.Image {
+	file, err := os.Open(path.Join("..", "testdata", name))
+	require.Nil(t, err)
+	defer func() {
+		if err := file.Close(); err != nil {
+			panic(err.Error())
+		}
+	}()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	return img
+}
+
+func TestDHash(t *testing.T) {
+
+	tests := []struct {
+		title string
+		img   image.Image
+		hash  uint64
+	}{
+		{
+			title: "empty image",
+			img:   image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			hash:  0,
+		},
+		{
+			title: "solid image",
+			img: build(18, 16, func(_ int, _ int) color.RGBA {
+				return color.RGBA{90, 180, 30, 0xFF}
+			}),
+			hash: 0,
+		},
+		{
+			title: "brightening to the right",
+			img: build(18, 16, func(x int, _ int) color.RGBA {
+				return color.RGBA{uint8(x * 14), uint8(x * 14), uint8(x * 14), 0xFF}
+			}),
+			hash: 0xFFFFFFFFFFFFFFFF,
+		},
+		{
+			title: "darkening to the right",
+			img: build(18, 16, func(x int, _ int) color.RGBA {
+				return color.RGBA{uint8(255 - x*14), 0, 0, 0xFF}
+			}),
+			hash: 0,
+		},
+		{
+			title: "brightening to the right in the top half",
+			img: build(9, 8, func(x int, y int) color.RGBA {
+				if y >= 4 {
+					return color.RGBA{0, 0, 0, 0xFF}
+				}
+				return color.RGBA{uint8(x * 20), 0, 0, 0xFF}
+			}),
+			hash: 0xFFFFFFFF00000000,
+		},
+		{
+			title: "smaller than the hash",
+			img: build(3, 1, func(x int, _ int) color.RGBA {
+				return color.RGBA{uint8(x * 100), 0, 0, 0xFF}
+			}),
+			hash: 0x2424242424242424,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.hash, DHash(test.img))
+
+		})
+	}
+
+}
+
+func TestPHash(t *testing.T) {
+
+	original := load(t, "plush.png")
+
+	tests := []struct {
+		title   string
+		img     image.Image
+		maximum int
+		minimum int
+	}{
+		{
+			title:   "identical image",
+			img:     original,
+			maximum: 0,
+		},
+		{
+			title:   "lossy encoding",
+			img:     load(t, "plush.jpg"),
+			maximum: 6,
+		},
+		{
+			title:   "reduced palette",
+			img:     load(t, "plush.gif"),
+			maximum: 6,
+		},
+		{
+			title: "inverted image",
+			img: build(original.Bounds().Dx(), original.Bounds().Dy(), func(x int, y int) color.RGBA {
+				r, g, b, _ := original.At(original.Bounds().Min.X+x, original.Bounds().Min.Y+y).RGBA()
+				return color.RGBA{255 - uint8(r>>8), 255 - uint8(g>>8), 255 - uint8(b>>8), 0xFF}
+			}),
+			maximum: 64,
+			minimum: 48,
+		},
+	}
+
+	hash := PHash(original)
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			distance := Distance(hash, PHash(test.img))
+
+			assert.True(t, distance <= test.maximum, "distance %d above %d", distance, test.maximum)
+			assert.True(t, distance >= test.minimum, "distance %d below %d", distance, test.minimum)
+
+		})
+	}
+
+}
+
+func TestDistance(t *testing.T) {
+
+	tests := []struct {
+		first    uint64
+		second   uint64
+		distance int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xF0, 0x0F, 8},
+		{0, 0xFFFFFFFFFFFFFFFF, 64},
+		{0xFFFF0000FFFF0000, 0xFFFF0000FFFF0000, 0},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %x %x", index, test.first, test.second)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.distance, Distance(test.first, test.second))
+			assert.Equal(t, test.distance, Distance(test.second, test.first))
+
+		})
+	}
+
+}
+
+func TestNewFingerprint(t *testing.T) {
+
+	img := load(t, "plush.jpg")
+
+	fingerprint := NewFingerprint(img, 3)
+
+	assert.Equal(t, quantize.Image(img, 3), fingerprint.Palette)
+	assert.Equal(t, DHash(img), fingerprint.DHash)
+	assert.Equal(t, PHash(img), fingerprint.PHash)
+
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// Layout divides the given image into a grid of cols by rows tiles, and
+// assigns each tile the index of its locally dominant color within the given
+// palette, counting each pixel towards its nearest palette color. Returns the
+// indices in row-major order, so the index for a given tile is at
+// row*cols+col. Tiles that hold no pixels are assigned -1. The result is a
+// compact color layout descriptor, suitable for image similarity search.
+func Layout(img image.Image, colors []color.RGBA, cols int, rows int) []int {
+
+	if cols <= 0 || rows <= 0 {
+		return []int{}
+	}
+
+	layout := make([]int, 0, cols*rows)
+	counts := make([]int, len(colors))
+	var pixels []color.RGBA
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+
+			for index := range counts {
+				counts[index] = 0
+			}
+
+			pixels = extract(pixels, img, tile(img.Bounds(), cols, rows, col, row))
+			if len(colors) > 0 {
+				for _, pixel := range pixels {
+					index, _ := nearest(colors, pixel)
+					counts[index]++
+				}
+			}
+
+			// Ties are broken in favor of the first color
+			dominant := -1
+			for index, count := range counts {
+				if count > 0 && (dominant < 0 || count > counts[dominant]) {
+					dominant = index
+				}
+			}
+
+			layout = append(layout, dominant)
+		}
+	}
+
+	return layout
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout(t *testing.T) {
+
+	var (
+		red   = color.RGBA{255, 0, 0, 0xFF}
+		green = color.RGBA{0, 255, 0, 0xFF}
+		blue  = color.RGBA{0, 0, 255, 0xFF}
+	)
+
+	// Red on top, with a green left half and blue right half below, and a
+	// single stray pixel in the top left
+	img := testImage(4, 4, func(x int, y int) color.RGBA {
+		switch {
+		case x == 0 && y == 0:
+			return color.RGBA{0, 200, 0, 0xFF}
+		case y < 2:
+			return red
+		case x < 2:
+			return green
+		default:
+			return blue
+		}
+	})
+
+	tests := []struct {
+		title  string
+		colors []color.RGBA
+		cols   int
+		rows   int
+		layout []int
+	}{
+		{
+			title:  "no tiles",
+			colors: []color.RGBA{red, green, blue},
+			cols:   0,
+			rows:   2,
+			layout: []int{},
+		},
+		{
+			title:  "single tile",
+			colors: []color.RGBA{red, green, blue},
+			cols:   1,
+			rows:   1,
+			layout: []int{0},
+		},
+		{
+			title:  "quadrants",
+			colors: []color.RGBA{red, green, blue},
+			cols:   2,
+			rows:   2,
+			layout: []int{0, 0, 1, 2},
+		},
+		{
+			title:  "every pixel",
+			colors: []color.RGBA{blue, green, red},
+			cols:   4,
+			rows:   2,
+			layout: []int{1, 2, 2, 2, 1, 1, 0, 0},
+		},
+		{
+			title:  "tiles without pixels",
+			colors: []color.RGBA{red, green, blue},
+			cols:   8,
+			rows:   1,
+			layout: []int{-1, 1, -1, 0, -1, 0, -1, 0},
+		},
+		{
+			title:  "empty palette",
+			colors: []color.RGBA{},
+			cols:   2,
+			rows:   1,
+			layout: []int{-1, -1},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.layout, Layout(img, test.colors, test.cols, test.rows))
+
+		})
+	}
+
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"math"
+)
+
+const (
+	// autoBits is the number of most significant bits of each color component
+	// used to group similar colors into coarse buckets.
+	autoBits = 4
+
+	// autoShare is the smallest fraction of the pixels that a coarse bucket
+	// must hold to count towards the color diversity of an image, so that
+	// noise and antialiasing are ignored.
+	autoShare = 0.01
+
+	// autoMinLevels & autoMaxLevels bound the levels chosen by LevelsAuto.
+	autoMinLevels = 1
+	autoMaxLevels = 5
+)
+
+// LevelsAuto takes in an image, and returns a number of levels suited to its
+// color diversity, for when none was chosen explicitly. Colors are grouped
+// into coarse buckets, and the levels are just enough for a palette color per
+// bucket holding a meaningful share of the pixels, so that a flat logo yields
+// only a few colors while a busy photograph yields many. Returns a number of
+// levels within [1, 5].
+func LevelsAuto(img image.Image) int {
+	pixels := extract(nil, img, img.Bounds())
+	if len(pixels) == 0 {
+		return autoMinLevels
+	}
+
+	const shift = 8 - autoBits
+	counts := make(map[uint16]int)
+
+	for _, pixel := range pixels {
+		bucket := uint16(pixel.R>>shift)<<(2*autoBits) | uint16(pixel.G>>shift)<<autoBits | uint16(pixel.B>>shift)
+		counts[bucket]++
+	}
+
+	var buckets int
+	for _, count := range counts {
+		if float64(count) >= autoShare*float64(len(pixels)) {
+			buckets++
+		}
+	}
+
+	levels := int(math.Ceil(math.Log2(float64(buckets))))
+
+	switch {
+	case levels < autoMinLevels:
+		return autoMinLevels
+	case levels > autoMaxLevels:
+		return autoMaxLevels
+	default:
+		return levels
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stripes builds an image of vertical stripes, one per given color.
+func stripes(colors ...color.RGBA) *image.RGBA {
+	return testImage(len(colors)*4, 4, func(x int, _ int) color.RGBA {
+		return colors[x/4]
+	})
+}
+
+func TestLevelsAuto(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.png"))
+	require.Nil(t, err)
+	defer file.Close()
+
+	photo, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	tests := []struct {
+		title  string
+		img    image.Image
+		levels int
+	}{
+		{
+			title:  "empty image",
+			img:    image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			levels: 1,
+		},
+		{
+			title:  "solid image",
+			img:    stripes(red),
+			levels: 1,
+		},
+		{
+			title:  "three colors",
+			img:    stripes(red, green, blue),
+			levels: 2,
+		},
+		{
+			title:  "five colors",
+			img:    stripes(red, green, blue, white, color.RGBA{0, 0, 0, 0xFF}),
+			levels: 3,
+		},
+		{
+			title:  "antialiased logo",
+			img:    logo(),
+			levels: 1,
+		},
+		{
+			title:  "photo",
+			img:    photo,
+			levels: 4,
+		},
+		{
+			title: "many colors",
+			img: testImage(64, 64, func(x int, y int) color.RGBA {
+				return color.RGBA{uint8(x / 8 * 32), uint8(y / 8 * 32), 0, 0xFF}
+			}),
+			levels: 5,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.levels, LevelsAuto(test.img))
+
+		})
+	}
+
+}
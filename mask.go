@@ -0,0 +1,52 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// Masks takes in an image and a palette, and returns one binary mask for
+// every palette color, in the same order. Each mask is white where the pixels
+// of the image are nearest to its color, and black elsewhere. When a color
+// appears more than once in the palette, only the first mask is filled.
+func Masks(img image.Image, colors []color.RGBA) []*image.Gray {
+	rect := img.Bounds()
+
+	masks := make([]*image.Gray, len(colors))
+	for index := range masks {
+		masks[index] = image.NewGray(rect)
+	}
+
+	if len(colors) == 0 {
+		return masks
+	}
+
+	for index, assigned := range remapIndices(img, colors) {
+		x, y := rect.Min.X+index%rect.Dx(), rect.Min.Y+index/rect.Dx()
+		masks[assigned].SetGray(x, y, color.Gray{0xFF})
+	}
+
+	return masks
+}
+
+// remapIndices returns the index of the nearest palette color to every pixel
+// of the given image, in row-major order. The palette must not be empty.
+func remapIndices(img image.Image, colors []color.RGBA) []int {
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	indices := make([]int, width*height)
+
+	// Pixels are extracted column by column, so their coordinates follow from
+	// their index alone
+	for index, pixel := range extract(nil, img, rect) {
+		x, y := index/height, index%height
+		indices[y*width+x], _ = nearest(colors, pixel)
+	}
+
+	return indices
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasks(t *testing.T) {
+
+	// The left column is reddish, and the right column is bluish
+	img := image.NewRGBA(image.Rect(10, 20, 12, 22))
+	img.SetRGBA(10, 20, color.RGBA{240, 10, 0, 0xFF})
+	img.SetRGBA(10, 21, color.RGBA{250, 0, 20, 0xFF})
+	img.SetRGBA(11, 20, color.RGBA{0, 0, 200, 0xFF})
+	img.SetRGBA(11, 21, color.RGBA{10, 20, 255, 0xFF})
+
+	tests := []struct {
+		title  string
+		colors []color.RGBA
+		masks  [][]uint8
+	}{
+		{
+			title:  "no colors",
+			colors: []color.RGBA{},
+			masks:  [][]uint8{},
+		},
+		{
+			title:  "one mask per color",
+			colors: []color.RGBA{red, green, blue},
+			masks: [][]uint8{
+				{0xFF, 0, 0xFF, 0},
+				{0, 0, 0, 0},
+				{0, 0xFF, 0, 0xFF},
+			},
+		},
+		{
+			title:  "repeated color",
+			colors: []color.RGBA{blue, blue, red},
+			masks: [][]uint8{
+				{0, 0xFF, 0, 0xFF},
+				{0, 0, 0, 0},
+				{0xFF, 0, 0xFF, 0},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			masks := Masks(img, test.colors)
+			require.Equal(t, len(test.masks), len(masks))
+
+			for index, mask := range masks {
+				assert.Equal(t, img.Bounds(), mask.Bounds())
+				assert.Equal(t, test.masks[index], mask.Pix)
+			}
+
+		})
+	}
+
+}
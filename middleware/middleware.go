@@ -0,0 +1,99 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package middleware annotates served images with their dominant color, so
+// that frontends & CDNs can paint a placeholder before an image loads.
+package middleware
+
+import (
+	"bytes"
+	"image"
+	"net/http"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+const (
+	// Header is the response header holding the dominant color of an image,
+	// as a hex color.
+	Header = "X-Dominant-Color"
+
+	// levels is the number of MMCQ levels used to find the dominant color.
+	levels = 3
+)
+
+// DominantColor wraps the given handler, and sets the dominant color header on
+// every successful response holding an image. Image responses are buffered in
+// full, since headers can not be changed once the body has been written, while
+// every other response is passed through untouched. Images are decoded with
+// image.Decode, so the decoder for every image format must be registered.
+func DominantColor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer := &writer{ResponseWriter: w}
+		next.ServeHTTP(writer, r)
+		writer.finish()
+	})
+}
+
+// writer buffers the response if it holds an image.
+type writer struct {
+	http.ResponseWriter
+
+	decided   bool
+	buffering bool
+	status    int
+	body      bytes.Buffer
+}
+
+func (w *writer) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	w.decide(status, nil)
+}
+
+func (w *writer) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(http.StatusOK, data)
+	}
+
+	if w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide chooses whether to buffer the response, based on its status and its
+// content type, which is sniffed from the first write if not set.
+func (w *writer) decide(status int, data []byte) {
+	w.decided, w.status = true, status
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && data != nil {
+		header.Set("Content-Type", http.DetectContentType(data))
+	}
+
+	w.buffering = status == http.StatusOK && strings.HasPrefix(header.Get("Content-Type"), "image/")
+	if !w.buffering {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// finish sets the dominant color header of a buffered image, and writes the
+// response. Images that can not be decoded are written without the header.
+func (w *writer) finish() {
+	if !w.buffering {
+		return
+	}
+
+	if img, _, err := image.Decode(bytes.NewReader(w.body.Bytes())); err == nil {
+		if result := quantize.Analyze(img, levels); len(result.Colors) > 0 {
+			w.Header().Set(Header, result.Colors[0].Hex)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
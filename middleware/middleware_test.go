@@ -0,0 +1,102 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDominantColor(t *testing.T) {
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for index := range img.Pix {
+		img.Pix[index] = []uint8{0, 0, 128, 0xFF}[index%4]
+	}
+	img.SetRGBA(0, 0, color.RGBA{255, 255, 0, 0xFF})
+
+	var buf bytes.Buffer
+	require.Nil(t, png.Encode(&buf, img))
+	encoded := buf.Bytes()
+
+	tests := []struct {
+		title    string
+		handler  http.HandlerFunc
+		status   int
+		body     []byte
+		dominant string
+	}{
+		{
+			title: "image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Write(encoded)
+			},
+			status:   http.StatusOK,
+			body:     encoded,
+			dominant: "#000080",
+		},
+		{
+			title: "sniffed image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(encoded[:10])
+				w.Write(encoded[10:])
+			},
+			status:   http.StatusOK,
+			body:     encoded,
+			dominant: "#000080",
+		},
+		{
+			title: "not an image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte("hello"))
+			},
+			status: http.StatusOK,
+			body:   []byte("hello"),
+		},
+		{
+			title: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.WriteHeader(http.StatusNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			title: "corrupt image",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				w.Write(encoded[:20])
+			},
+			status: http.StatusOK,
+			body:   encoded[:20],
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			recorder := httptest.NewRecorder()
+			DominantColor(test.handler).ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, test.status, recorder.Code)
+			assert.Equal(t, test.body, recorder.Body.Bytes())
+			assert.Equal(t, test.dominant, recorder.Header().Get(Header))
+
+		})
+	}
+}
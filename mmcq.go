@@ -54,6 +54,13 @@ func Partition(pixels []color.RGBA) ([]color.RGBA, []color.RGBA) {
 
 	deltaR, deltaG, deltaB := Spread(pixels)
 
+	return bisect(pixels, deltaR, deltaG, deltaB)
+}
+
+// bisect sorts the given pixels by the color component with the largest of
+// the given spreads, and then splits them in half.
+func bisect(pixels []color.RGBA, deltaR uint8, deltaG uint8, deltaB uint8) ([]color.RGBA, []color.RGBA) {
+
 	var less func(int, int) bool
 
 	switch {
@@ -83,65 +90,106 @@ func Partition(pixels []color.RGBA) ([]color.RGBA, []color.RGBA) {
 }
 
 // Average takes in a slice of RGB pixels, and returns the average across the
-// red, green, & blue components of all pixels.
+// red, green, & blue components of all pixels, rounded to the nearest value.
+// Components are accumulated as 64-bit integers, so partitions of up to 2^56
+// pixels are supported on every platform, including those where int is only
+// 32 bits.
 func Average(pixels []color.RGBA) color.RGBA {
-	var totalR int
-	var totalG int
-	var totalB int
+	return averageOf(pixels, false)
+}
+
+// averageOf returns the average across the red, green, & blue components of
+// all pixels, either rounded or truncated.
+func averageOf(pixels []color.RGBA, truncate bool) color.RGBA {
+	var totalR uint64
+	var totalG uint64
+	var totalB uint64
 
 	if len(pixels) == 0 {
 		return color.RGBA{0, 0, 0, 0xFF}
 	}
 
 	for _, pixel := range pixels {
-		totalR += int(pixel.R)
-		totalG += int(pixel.G)
-		totalB += int(pixel.B)
+		totalR += uint64(pixel.R)
+		totalG += uint64(pixel.G)
+		totalB += uint64(pixel.B)
+	}
+
+	return average(totalR, totalG, totalB, uint64(len(pixels)), truncate)
+}
+
+// average returns the average color given the component totals across the
+// given number of pixels, either rounded to the nearest value or truncated.
+func average(totalR uint64, totalG uint64, totalB uint64, count uint64, truncate bool) color.RGBA {
+
+	// Adding half of the divisor rounds the quotient to the nearest value
+	var half uint64
+	if !truncate {
+		half = count / 2
 	}
 
 	return color.RGBA{
-		uint8(totalR / len(pixels)),
-		uint8(totalG / len(pixels)),
-		uint8(totalB / len(pixels)),
+		uint8((totalR + half) / count),
+		uint8((totalG + half) / count),
+		uint8((totalB + half) / count),
 		0xFF,
 	}
 }
 
 // Pixels takes in a slice of RGB pixels, and performs the MMCQ process to the
 // specified number of levels. Returns a slice of RGB colors of length 2^levels.
+// Levels outside of [0, MaxLevels] are clamped.
 func Pixels(pixels []color.RGBA, levels int) []color.RGBA {
+	var quantizer Quantizer
+	levels = clampLevels(levels)
+	return quantizer.quantize(pixels, nil, levels, 1<<uint(levels))
+}
+
+// Image is a helper that converts the given image into a slice of RGB pixels
+// before performing MMCQ. Levels outside of [0, MaxLevels] are clamped.
+func Image(img image.Image, levels int) []color.RGBA {
+	var quantizer Quantizer
+	return quantizer.region(img, img.Bounds(), clampLevels(levels))
+}
 
-	partitions := [][]color.RGBA{
-		pixels,
+// Colors takes in an image, and performs MMCQ until the palette holds exactly
+// n colors, which need not be a power of two. A number of colors outside of
+// [1, MaxColors] is clamped.
+func Colors(img image.Image, n int) []color.RGBA {
+	switch {
+	case n < 1:
+		n = 1
+	case n > MaxColors:
+		n = MaxColors
 	}
 
-	for iteration := 0; iteration < levels; iteration++ {
+	var quantizer Quantizer
+	colors, _ := quantizer.Colors(img, n)
+	return colors
+}
 
-		next := [][]color.RGBA{}
+// extract appends the pixels of the given image that lie within the given
+// rectangle to the given buffer, after truncating it, as RGB pixels.
+func extract(buf []color.RGBA, img image.Image, rect image.Rectangle) []color.RGBA {
 
-		for _, partition := range partitions {
-			left, right := Partition(partition)
-			next = append(next, left, right)
-		}
+	rect = rect.Intersect(img.Bounds())
 
-		partitions = next
+	// Only grow the buffer if it is too small to hold every pixel
+	if count := rect.Dx() * rect.Dy(); cap(buf) < count {
+		buf = make([]color.RGBA, 0, count)
 	}
+	pixels := buf[:0]
 
-	averages := make([]color.RGBA, len(partitions))
-
-	for index, partition := range partitions {
-		averages[index] = Average(partition)
+	// Paletted images only need each palette color to be converted once
+	if paletted, ok := img.(*image.Paletted); ok {
+		return extractPaletted(pixels, paletted, rect)
 	}
 
-	return averages
-}
-
-// Image is a helper that converts the given image into a slice of RGB pixels
-// before performing MMCQ.
-func Image(img image.Image, levels int) []color.RGBA {
-
-	rect := img.Bounds()
-	pixels := make([]color.RGBA, 0, rect.Max.X*rect.Max.Y)
+	// Images from NRGBA pipelines are read directly, without boxing every
+	// pixel as a color.Color
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return extractPremultiplied(pixels, nrgba, rect)
+	}
 
 	for x := rect.Min.X; x < rect.Max.X; x++ {
 		for y := rect.Min.Y; y < rect.Max.Y; y++ {
@@ -159,7 +207,105 @@ func Image(img image.Image, levels int) []color.RGBA {
 		}
 	}
 
-	return Pixels(pixels, levels)
+	return pixels
+}
+
+// extractPaletted appends the pixels of the given paletted image that lie
+// within the given rectangle to the given slice, by indexing into a
+// precomputed table of palette colors.
+func extractPaletted(pixels []color.RGBA, img *image.Paletted, rect image.Rectangle) []color.RGBA {
+
+	table := paletteTable(img)
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			pixels = append(pixels, table[img.Pix[img.PixOffset(x, y)]])
+		}
+	}
+
+	return pixels
+}
+
+// histogramPaletted appends every palette color of the given paletted image
+// that occurs within the given rectangle to the given slice, along with how
+// many times it occurs to the given weights, after truncating both. Only the
+// palette indices are counted, so reducing the result scales with the size of
+// the palette rather than the size of the image.
+func histogramPaletted(pixels []color.RGBA, weights []float64, img *image.Paletted, rect image.Rectangle) ([]color.RGBA, []float64) {
+
+	rect = rect.Intersect(img.Bounds())
+	pixels, weights = pixels[:0], weights[:0]
+
+	var counts [256]int
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		offset := img.PixOffset(rect.Min.X, y)
+		for _, index := range img.Pix[offset : offset+rect.Dx()] {
+			counts[index]++
+		}
+	}
+
+	table := paletteTable(img)
+
+	for index, count := range counts {
+		if count > 0 {
+			pixels = append(pixels, table[index])
+			weights = append(weights, float64(count))
+		}
+	}
+
+	return pixels, weights
+}
+
+// paletteTable converts every palette color of the given paletted image to an
+// RGB pixel, indexed by palette index. Indices outside of the palette are
+// treated as black.
+func paletteTable(img *image.Paletted) *[256]color.RGBA {
+	var table [256]color.RGBA
+	for index := range table {
+		table[index] = color.RGBA{0, 0, 0, 0xFF}
+	}
+
+	for index, clr := range img.Palette {
+		if index >= len(table) {
+			break
+		}
+
+		r, g, b, _ := clr.RGBA()
+
+		table[index] = color.RGBA{
+			uint8(r >> 8),
+			uint8(g >> 8),
+			uint8(b >> 8),
+			0xFF,
+		}
+	}
+
+	return &table
+}
+
+// extractPremultiplied appends the pixels of the given NRGBA image that lie
+// within the given rectangle to the given slice, premultiplied by their alpha
+// exactly as color.NRGBA does.
+func extractPremultiplied(pixels []color.RGBA, img *image.NRGBA, rect image.Rectangle) []color.RGBA {
+
+	premultiply := func(component uint8, alpha uint8) uint8 {
+		return uint8((uint32(component) * 0x101 * uint32(alpha) / 0xFF) >> 8)
+	}
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			pix := img.Pix[img.PixOffset(x, y):]
+
+			pixels = append(pixels, color.RGBA{
+				premultiply(pix[0], pix[3]),
+				premultiply(pix[1], pix[3]),
+				premultiply(pix[2], pix[3]),
+				0xFF,
+			})
+		}
+	}
+
+	return pixels
 }
 
 func min(first uint8, second uint8) uint8 {
@@ -131,7 +131,7 @@ func TestAverage(t *testing.T) {
 				{213, 125, 245, 0xFF},
 				{251, 125, 26, 0xFF},
 			},
-			average: color.RGBA{117, 136, 99, 0xFF},
+			average: color.RGBA{118, 136, 99, 0xFF},
 		},
 	}
 
@@ -149,6 +149,63 @@ func TestAverage(t *testing.T) {
 
 }
 
+func TestAverageGiant(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		count   uint64
+		pixel   color.RGBA
+		average color.RGBA
+	}{
+		{
+			title:   "exceeds 32 bit totals",
+			count:   300000000, // 300 megapixels
+			pixel:   color.RGBA{255, 128, 1, 0xFF},
+			average: color.RGBA{255, 128, 1, 0xFF},
+		},
+		{
+			title:   "exceeds 32 bit counts",
+			count:   1 << 40,
+			pixel:   color.RGBA{255, 255, 255, 0xFF},
+			average: color.RGBA{255, 255, 255, 0xFF},
+		},
+		{
+			title:   "documented limit",
+			count:   1 << 56,
+			pixel:   color.RGBA{255, 255, 255, 0xFF},
+			average: color.RGBA{255, 255, 255, 0xFF},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			// Synthesize the totals of a partition too large to allocate
+			actual := average(
+				uint64(test.pixel.R)*test.count,
+				uint64(test.pixel.G)*test.count,
+				uint64(test.pixel.B)*test.count,
+				test.count,
+				false,
+			)
+
+			assert.Equal(t, test.average, actual)
+
+		})
+	}
+
+	// A real partition large enough to overflow 16 bit accumulators
+	pixels := make([]color.RGBA, 1<<20)
+	for index := range pixels {
+		pixels[index] = color.RGBA{255, uint8(index % 2 * 255), 0, 0xFF}
+	}
+
+	assert.Equal(t, color.RGBA{255, 128, 0, 0xFF}, Average(pixels))
+
+}
+
 func TestSpread(t *testing.T) {
 
 	tests := []struct {
@@ -386,7 +443,7 @@ func TestPixels(t *testing.T) {
 			},
 			levels: 1,
 			palette: []color.RGBA{
-				{0, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
 				{255, 0, 0, 0xFF},
 			},
 		},
@@ -397,14 +454,50 @@ func TestPixels(t *testing.T) {
 			},
 			levels: 3,
 			palette: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "2 colors 2 levels",
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+			levels: 2,
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{255, 0, 0, 0xFF},
+				{0, 0, 255, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "exhausted partition redistributed",
+			pixels: []color.RGBA{
 				{0, 0, 0, 0xFF},
 				{0, 0, 0, 0xFF},
 				{0, 0, 0, 0xFF},
 				{0, 0, 0, 0xFF},
+				{200, 0, 0, 0xFF},
+				{210, 0, 0, 0xFF},
+				{220, 0, 0, 0xFF},
+				{230, 0, 0, 0xFF},
+				{240, 0, 0, 0xFF},
+			},
+			levels: 2,
+			palette: []color.RGBA{
 				{0, 0, 0, 0xFF},
-				{0, 0, 0, 0xFF},
-				{0, 0, 0, 0xFF},
-				{255, 0, 0, 0xFF},
+				{205, 0, 0, 0xFF},
+				{220, 0, 0, 0xFF},
+				{235, 0, 0, 0xFF},
 			},
 		},
 		{
@@ -417,7 +510,7 @@ func TestPixels(t *testing.T) {
 			},
 			levels: 0,
 			palette: []color.RGBA{
-				{4, 2, 1, 0xFF},
+				{4, 2, 2, 0xFF},
 			},
 		},
 		{
@@ -471,16 +564,27 @@ func TestPixels(t *testing.T) {
 func TestImage(t *testing.T) {
 
 	tests := []struct {
-		title   string
-		path    string
-		levels  int
-		palette []color.RGBA
+		title     string
+		path      string
+		levels    int
+		palette   []color.RGBA
+		truncated []color.RGBA
 	}{
 		{
 			title:  "jpg file",
 			path:   "plush.jpg",
 			levels: 3,
 			palette: []color.RGBA{
+				{R: 0x14, G: 0x26, B: 0x5d, A: 0xff},
+				{R: 0x77, G: 0x5b, B: 0x4b, A: 0xff},
+				{R: 0x32, G: 0x53, B: 0x9a, A: 0xff},
+				{R: 0x7f, G: 0x94, B: 0xb1, A: 0xff},
+				{R: 0xba, G: 0x8c, B: 0x60, A: 0xff},
+				{R: 0xd9, G: 0xcd, B: 0xbe, A: 0xff},
+				{R: 0xe5, G: 0xe2, B: 0xd8, A: 0xff},
+				{R: 0xf8, G: 0xf4, B: 0xe9, A: 0xff},
+			},
+			truncated: []color.RGBA{
 				{R: 0x13, G: 0x25, B: 0x5c, A: 0xff},
 				{R: 0x76, G: 0x5b, B: 0x4b, A: 0xff},
 				{R: 0x31, G: 0x52, B: 0x99, A: 0xff},
@@ -496,6 +600,16 @@ func TestImage(t *testing.T) {
 			path:   "plush.png",
 			levels: 3,
 			palette: []color.RGBA{
+				{R: 0x14, G: 0x26, B: 0x5d, A: 0xff},
+				{R: 0x77, G: 0x5b, B: 0x4b, A: 0xff},
+				{R: 0x32, G: 0x53, B: 0x9a, A: 0xff},
+				{R: 0x7f, G: 0x94, B: 0xb1, A: 0xff},
+				{R: 0xb9, G: 0x8c, B: 0x60, A: 0xff},
+				{R: 0xd8, G: 0xcd, B: 0xbe, A: 0xff},
+				{R: 0xe4, G: 0xe2, B: 0xd9, A: 0xff},
+				{R: 0xf9, G: 0xf3, B: 0xe8, A: 0xff},
+			},
+			truncated: []color.RGBA{
 				{R: 0x14, G: 0x25, B: 0x5d, A: 0xff},
 				{R: 0x76, G: 0x5b, B: 0x4b, A: 0xff},
 				{R: 0x32, G: 0x52, B: 0x99, A: 0xff},
@@ -510,7 +624,21 @@ func TestImage(t *testing.T) {
 			title:  "gif file",
 			path:   "plush.gif",
 			levels: 3,
+			// Paletted images are reduced from a histogram of their palette
+			// colors, so each color falls entirely within a single partition
 			palette: []color.RGBA{
+				{R: 0x14, G: 0x26, B: 0x5d, A: 0xff},
+				{R: 0x78, G: 0x5b, B: 0x49, A: 0xff},
+				{R: 0x30, G: 0x52, B: 0x9a, A: 0xff},
+				{R: 0x7e, G: 0x91, B: 0xaf, A: 0xff},
+				{R: 0xb8, G: 0x89, B: 0x5a, A: 0xff},
+				{R: 0xd8, G: 0xcc, B: 0xbc, A: 0xff},
+				{R: 0xe2, G: 0xe1, B: 0xd9, A: 0xff},
+				{R: 0xf8, G: 0xf2, B: 0xe5, A: 0xff},
+			},
+			// Truncated averages always expand every pixel, and so match
+			// the palette of earlier releases exactly
+			truncated: []color.RGBA{
 				{R: 0x13, G: 0x26, B: 0x5d, A: 0xff},
 				{R: 0x78, G: 0x5a, B: 0x49, A: 0xff},
 				{R: 0x31, G: 0x53, B: 0x9b, A: 0xff},
@@ -545,7 +673,159 @@ func TestImage(t *testing.T) {
 
 			assert.Equal(t, test.palette, palette)
 
+			truncated, err := NewQuantizer(WithTruncatedAverage()).Image(img, test.levels)
+			require.Nil(t, err)
+
+			assert.Equal(t, test.truncated, truncated)
+
 		})
 	}
 
 }
+
+// opaqueImage hides the concrete type of the wrapped image, forcing pixels to
+// be read through the generic image.Image interface.
+type opaqueImage struct {
+	image.Image
+}
+
+func TestExtractPaletted(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.gif"))
+	require.Nil(t, err)
+	defer func() {
+		if err := file.Close(); err != nil {
+			panic(err.Error())
+		}
+	}()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	paletted, ok := img.(*image.Paletted)
+	require.True(t, ok)
+
+	tests := []struct {
+		title string
+		rect  image.Rectangle
+	}{
+		{
+			title: "full image",
+			rect:  paletted.Bounds(),
+		},
+		{
+			title: "partial image",
+			rect:  image.Rect(10, 20, 60, 40),
+		},
+		{
+			title: "outside image",
+			rect:  image.Rect(-20, -20, -10, -10),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			expected := extract(nil, opaqueImage{paletted}, test.rect)
+			actual := extract(nil, paletted, test.rect)
+
+			assert.Equal(t, expected, actual)
+
+		})
+	}
+
+}
+
+func TestHistogramPaletted(t *testing.T) {
+
+	file, err := os.Open(path.Join("testdata", "plush.gif"))
+	require.Nil(t, err)
+	defer func() {
+		if err := file.Close(); err != nil {
+			panic(err.Error())
+		}
+	}()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	paletted, ok := img.(*image.Paletted)
+	require.True(t, ok)
+
+	tests := []struct {
+		title string
+		rect  image.Rectangle
+	}{
+		{
+			title: "full image",
+			rect:  paletted.Bounds(),
+		},
+		{
+			title: "partial image",
+			rect:  image.Rect(10, 20, 60, 40),
+		},
+		{
+			title: "outside image",
+			rect:  image.Rect(-20, -20, -10, -10),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			expected := map[color.RGBA]float64{}
+			for _, pixel := range extract(nil, paletted, test.rect) {
+				expected[pixel]++
+			}
+
+			pixels, weights := histogramPaletted(nil, nil, paletted, test.rect)
+			require.Equal(t, len(pixels), len(weights))
+
+			actual := map[color.RGBA]float64{}
+			for index, pixel := range pixels {
+				actual[pixel] += weights[index]
+			}
+
+			assert.Equal(t, expected, actual)
+			assert.True(t, len(pixels) <= len(paletted.Palette))
+
+		})
+	}
+
+}
+
+func TestClampLevels(t *testing.T) {
+
+	pixels := []color.RGBA{
+		{255, 0, 0, 0xFF},
+		{0, 0, 255, 0xFF},
+	}
+
+	assert.Equal(t, Pixels(pixels, 0), Pixels(pixels, -3))
+	assert.Equal(t, MaxColors, len(Pixels(pixels, MaxLevels+14)))
+
+}
+
+func TestColors(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), 0, 0xFF}
+	})
+
+	for index, n := range []int{1, 3, 5, 8, 300} {
+		name := fmt.Sprintf("Case #%d - %d colors", index, n)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, n, len(Colors(img, n)))
+
+		})
+	}
+
+	assert.Equal(t, 1, len(Colors(img, -1)))
+
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image/color"
+)
+
+// maxOptimizedColors is the largest palette whose similarity order is refined
+// with 2-opt, which is quadratic in the number of colors for every pass.
+const maxOptimizedColors = 256
+
+// SimilarityOrder takes in a slice of RGB colors, and returns them reordered so
+// that adjacent colors are perceptually close, which improves the compression
+// of paletted PNG & GIF images, and looks better when palettes are displayed
+// as strips. Starting from the darkest color, the nearest remaining color by
+// DeltaE is visited next. For palettes of up to 256 colors, the resulting path
+// is then refined by reversing any run of colors that shortens it.
+func SimilarityOrder(colors []color.RGBA) []color.RGBA {
+
+	ordered := make([]color.RGBA, len(colors))
+	copy(ordered, colors)
+
+	if len(ordered) < 3 {
+		return ordered
+	}
+
+	// Start from the darkest color
+	for index := range ordered {
+		if Luminance(ordered[index]) < Luminance(ordered[0]) {
+			ordered[0], ordered[index] = ordered[index], ordered[0]
+		}
+	}
+
+	// Greedily visit the nearest remaining color
+	for index := 1; index < len(ordered); index++ {
+		nearest := index
+		for other := index + 1; other < len(ordered); other++ {
+			if DeltaE(ordered[index-1], ordered[other]) < DeltaE(ordered[index-1], ordered[nearest]) {
+				nearest = other
+			}
+		}
+		ordered[index], ordered[nearest] = ordered[nearest], ordered[index]
+	}
+
+	if len(ordered) <= maxOptimizedColors {
+		twoOpt(ordered)
+	}
+
+	return ordered
+}
+
+// twoOpt repeatedly reverses runs of colors within the given path, whenever
+// doing so shortens the path, until no reversal does. The first color is kept
+// in place.
+func twoOpt(path []color.RGBA) {
+
+	// Ignore improvements too small to matter, so that rounding errors cannot
+	// cause reversals to repeat forever
+	const epsilon = 1e-9
+
+	for improved := true; improved; {
+		improved = false
+
+		for i := 1; i < len(path)-1; i++ {
+			for j := i + 1; j < len(path); j++ {
+
+				// Reversing path[i:j+1] replaces the edges on either side of it
+				before := DeltaE(path[i-1], path[i])
+				after := DeltaE(path[i-1], path[j])
+				if j+1 < len(path) {
+					before += DeltaE(path[j], path[j+1])
+					after += DeltaE(path[i], path[j+1])
+				}
+
+				if after < before-epsilon {
+					for left, right := i, j; left < right; left, right = left+1, right-1 {
+						path[left], path[right] = path[right], path[left]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+}
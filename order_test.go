@@ -0,0 +1,117 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pathLength returns the total DeltaE between adjacent colors.
+func pathLength(colors []color.RGBA) float64 {
+	var length float64
+	for index := 1; index < len(colors); index++ {
+		length += DeltaE(colors[index-1], colors[index])
+	}
+	return length
+}
+
+func TestSimilarityOrder(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		colors  []color.RGBA
+		ordered []color.RGBA
+	}{
+		{
+			title:   "empty palette",
+			colors:  []color.RGBA{},
+			ordered: []color.RGBA{},
+		},
+		{
+			title: "two colors",
+			colors: []color.RGBA{
+				{255, 255, 255, 0xFF},
+				{0, 0, 0, 0xFF},
+			},
+			ordered: []color.RGBA{
+				{255, 255, 255, 0xFF},
+				{0, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "grays",
+			colors: []color.RGBA{
+				{128, 128, 128, 0xFF},
+				{255, 255, 255, 0xFF},
+				{0, 0, 0, 0xFF},
+				{64, 64, 64, 0xFF},
+				{192, 192, 192, 0xFF},
+			},
+			ordered: []color.RGBA{
+				{0, 0, 0, 0xFF},
+				{64, 64, 64, 0xFF},
+				{128, 128, 128, 0xFF},
+				{192, 192, 192, 0xFF},
+				{255, 255, 255, 0xFF},
+			},
+		},
+		{
+			title: "reds and blues",
+			colors: []color.RGBA{
+				{0, 0, 200, 0xFF},
+				{200, 0, 0, 0xFF},
+				{0, 0, 100, 0xFF},
+				{100, 0, 0, 0xFF},
+			},
+			ordered: []color.RGBA{
+				{0, 0, 100, 0xFF},
+				{0, 0, 200, 0xFF},
+				{100, 0, 0, 0xFF},
+				{200, 0, 0, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.ordered, SimilarityOrder(test.colors))
+
+		})
+	}
+
+}
+
+func TestSimilarityOrderShorter(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), uint8(255 - x*y), 0xFF}
+	})
+
+	palette := Image(img, 5)
+	ordered, err := NewQuantizer(WithSimilarityOrder()).Image(img, 5)
+	require.Nil(t, err)
+
+	require.Equal(t, len(palette), len(ordered))
+
+	// Every color must be kept, merely reordered
+	counts := map[color.RGBA]int{}
+	for index := range palette {
+		counts[palette[index]]++
+		counts[ordered[index]]--
+	}
+	for _, count := range counts {
+		assert.Equal(t, 0, count)
+	}
+	assert.True(t, pathLength(ordered) < pathLength(palette))
+
+}
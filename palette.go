@@ -0,0 +1,239 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+)
+
+// paletteMagic is the header that prefixes every binary encoded palette.
+var paletteMagic = []byte("QPAL")
+
+// paletteVersion is the current version of the binary palette encoding.
+const paletteVersion = 1
+
+// resultMagic is the header that prefixes every binary encoded result.
+var resultMagic = []byte("QRES")
+
+// resultVersion is the current version of the binary result encoding.
+const resultVersion = 1
+
+// minSwatchSize is the fewest number of bytes that a binary encoded swatch
+// can occupy.
+const minSwatchSize = 4 + 1 + 8 + 1
+
+// ErrInvalidPalette is returned when decoding malformed palette data.
+var ErrInvalidPalette = errors.New("invalid palette data")
+
+// Palette is a slice of RGB colors, as returned by MMCQ, that can be stored
+// and reloaded later without needing the source image.
+type Palette []color.RGBA
+
+// MarshalJSON encodes the palette as a JSON array of hex color strings.
+func (p Palette) MarshalJSON() ([]byte, error) {
+	colors := make([]string, len(p))
+
+	for index, clr := range p {
+		if clr.A == 0xFF {
+			colors[index] = Hex(clr)
+		} else {
+			colors[index] = HexAlpha(clr)
+		}
+	}
+
+	return json.Marshal(colors)
+}
+
+// UnmarshalJSON decodes a JSON array of hex color strings into the palette.
+func (p *Palette) UnmarshalJSON(data []byte) error {
+	var colors []string
+
+	if err := json.Unmarshal(data, &colors); err != nil {
+		return err
+	}
+
+	palette := make(Palette, len(colors))
+
+	for index, str := range colors {
+		clr, err := ParseHex(str)
+		if err != nil {
+			return err
+		}
+		palette[index] = clr
+	}
+
+	*p = palette
+	return nil
+}
+
+// MarshalBinary encodes the palette into a compact binary form, consisting of
+// a short header, the number of colors, and then 4 bytes per RGBA color.
+func (p Palette) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	var count [binary.MaxVarintLen64]byte
+
+	buf.Write(paletteMagic)
+	buf.WriteByte(paletteVersion)
+	buf.Write(count[:binary.PutUvarint(count[:], uint64(len(p)))])
+
+	for _, clr := range p {
+		buf.Write([]byte{clr.R, clr.G, clr.B, clr.A})
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a palette previously encoded with MarshalBinary.
+func (p *Palette) UnmarshalBinary(data []byte) error {
+
+	// Verify the header and encoding version
+	if !bytes.HasPrefix(data, paletteMagic) || len(data) < len(paletteMagic)+1 {
+		return ErrInvalidPalette
+	}
+	data = data[len(paletteMagic):]
+
+	if data[0] != paletteVersion {
+		return fmt.Errorf("unsupported palette version %d", data[0])
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return ErrInvalidPalette
+	}
+	data = data[n:]
+
+	// Every color must be fully present, with no trailing data. The count is
+	// checked before multiplying, since a huge count would overflow
+	if count > uint64(len(data))/4 || uint64(len(data)) != count*4 {
+		return ErrInvalidPalette
+	}
+
+	palette := make(Palette, count)
+
+	for index := range palette {
+		palette[index] = color.RGBA{data[0], data[1], data[2], data[3]}
+		data = data[4:]
+	}
+
+	*p = palette
+	return nil
+}
+
+// MarshalBinary encodes the result into a compact binary form, consisting of a
+// short header, the number of pixels and colors, and then every color along
+// with its population, proportion, and exemplar. The remaining swatch fields
+// are derived from the color when decoding.
+func (r Result) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+
+	buf.Write(resultMagic)
+	buf.WriteByte(resultVersion)
+	buf.Write(scratch[:binary.PutUvarint(scratch[:], uint64(r.Pixels))])
+	buf.Write(scratch[:binary.PutUvarint(scratch[:], uint64(len(r.Colors)))])
+
+	for _, swatch := range r.Colors {
+		clr := swatch.RGBA
+		buf.Write([]byte{clr.R, clr.G, clr.B, clr.A})
+		buf.Write(scratch[:binary.PutUvarint(scratch[:], uint64(swatch.Population))])
+
+		binary.BigEndian.PutUint64(scratch[:8], math.Float64bits(swatch.Proportion))
+		buf.Write(scratch[:8])
+
+		if swatch.Exemplar == nil {
+			buf.WriteByte(0)
+			continue
+		}
+
+		buf.WriteByte(1)
+		buf.Write(scratch[:binary.PutVarint(scratch[:], int64(swatch.Exemplar.X))])
+		buf.Write(scratch[:binary.PutVarint(scratch[:], int64(swatch.Exemplar.Y))])
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a result previously encoded with MarshalBinary.
+func (r *Result) UnmarshalBinary(data []byte) error {
+
+	// Verify the header and encoding version
+	if !bytes.HasPrefix(data, resultMagic) || len(data) < len(resultMagic)+1 {
+		return ErrInvalidPalette
+	}
+	data = data[len(resultMagic):]
+
+	if data[0] != resultVersion {
+		return fmt.Errorf("unsupported result version %d", data[0])
+	}
+	reader := bytes.NewReader(data[1:])
+
+	pixels, err := binary.ReadUvarint(reader)
+	if err != nil || pixels > math.MaxInt32 {
+		return ErrInvalidPalette
+	}
+
+	count, err := binary.ReadUvarint(reader)
+	if err != nil || count > uint64(reader.Len())/minSwatchSize {
+		return ErrInvalidPalette
+	}
+
+	result := Result{
+		Colors: make([]Swatch, count),
+		Pixels: int(pixels),
+	}
+
+	for index := range result.Colors {
+		var fixed [4]byte
+		if _, err := io.ReadFull(reader, fixed[:]); err != nil {
+			return ErrInvalidPalette
+		}
+
+		population, err := binary.ReadUvarint(reader)
+		if err != nil || population > math.MaxInt32 {
+			return ErrInvalidPalette
+		}
+
+		var proportion [8]byte
+		if _, err := io.ReadFull(reader, proportion[:]); err != nil {
+			return ErrInvalidPalette
+		}
+
+		clr := color.RGBA{fixed[0], fixed[1], fixed[2], fixed[3]}
+		swatch := newSwatch(clr, int(population), math.Float64frombits(binary.BigEndian.Uint64(proportion[:])))
+
+		flag, err := reader.ReadByte()
+		if err != nil || flag > 1 {
+			return ErrInvalidPalette
+		}
+
+		if flag == 1 {
+			x, errX := binary.ReadVarint(reader)
+			y, errY := binary.ReadVarint(reader)
+			if errX != nil || errY != nil || x != int64(int32(x)) || y != int64(int32(y)) {
+				return ErrInvalidPalette
+			}
+			swatch.Exemplar = &image.Point{X: int(x), Y: int(y)}
+		}
+
+		result.Colors[index] = swatch
+	}
+
+	// No trailing data is allowed
+	if reader.Len() != 0 {
+		return ErrInvalidPalette
+	}
+
+	*r = result
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"io"
+	"io/ioutil"
+)
+
+// actColors is the number of colors held by an Adobe color table.
+const actColors = 256
+
+// EncodeACT writes the given palette as an Adobe color table, as used by
+// Photoshop, padded to 256 colors and followed by the number of colors that
+// are in use. Palettes of more than 256 colors cannot be written.
+func EncodeACT(w io.Writer, colors []color.RGBA) error {
+	if len(colors) > actColors {
+		return errors.New("act: palette holds more than 256 colors")
+	}
+
+	table := make([]byte, actColors*3+4)
+	for index, clr := range colors {
+		table[index*3], table[index*3+1], table[index*3+2] = clr.R, clr.G, clr.B
+	}
+
+	// No color is marked as transparent
+	binary.BigEndian.PutUint16(table[actColors*3:], uint16(len(colors)))
+	binary.BigEndian.PutUint16(table[actColors*3+2:], 0xFFFF)
+
+	_, err := w.Write(table)
+	return err
+}
+
+// DecodeACT reads an Adobe color table, which holds 256 colors optionally
+// followed by the number of colors that are in use and the index of a
+// transparent color. Every color is read as opaque.
+func DecodeACT(r io.Reader) ([]color.RGBA, error) {
+	table, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	count := actColors
+
+	switch len(table) {
+	case actColors * 3:
+	case actColors*3 + 4:
+		count = int(binary.BigEndian.Uint16(table[actColors*3:]))
+		if count > actColors {
+			return nil, errors.New("act: invalid color count")
+		}
+	default:
+		return nil, errors.New("act: invalid color table size")
+	}
+
+	colors := make([]color.RGBA, count)
+	for index := range colors {
+		colors[index] = color.RGBA{table[index*3], table[index*3+1], table[index*3+2], 0xFF}
+	}
+
+	return colors, nil
+}
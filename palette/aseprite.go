@@ -0,0 +1,149 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"io"
+)
+
+// The chunk types written to an Aseprite file.
+const (
+	asepriteLayerChunk   = 0x2004
+	asepritePaletteChunk = 0x2019
+)
+
+// asepriteHeader is the 128 byte header of an Aseprite file.
+type asepriteHeader struct {
+	Size        uint32
+	Magic       uint16
+	Frames      uint16
+	Width       uint16
+	Height      uint16
+	Depth       uint16
+	Flags       uint32
+	Speed       uint16
+	_           [2]uint32
+	Transparent uint8
+	_           [3]uint8
+	Colors      uint16
+	PixelWidth  uint8
+	PixelHeight uint8
+	GridX       int16
+	GridY       int16
+	GridWidth   uint16
+	GridHeight  uint16
+	_           [84]uint8
+}
+
+// asepriteFrame is the 16 byte header of a single Aseprite frame.
+type asepriteFrame struct {
+	Size      uint32
+	Magic     uint16
+	OldChunks uint16
+	Duration  uint16
+	_         [2]uint8
+	Chunks    uint32
+}
+
+// asepriteLayer is the body of a layer chunk, without the layer name.
+type asepriteLayer struct {
+	Flags      uint16
+	Type       uint16
+	ChildLevel uint16
+	_          [2]uint16
+	BlendMode  uint16
+	Opacity    uint8
+	_          [3]uint8
+}
+
+// asepritePalette is the body of a palette chunk, without its entries.
+type asepritePalette struct {
+	Size  uint32
+	First uint32
+	Last  uint32
+	_     [8]uint8
+}
+
+// asepriteEntry is a single palette chunk entry.
+type asepriteEntry struct {
+	Flags      uint16
+	R, G, B, A uint8
+}
+
+// EncodeAseprite writes the given palette as an .aseprite file, holding a 1x1
+// sprite with a single empty layer and the palette itself. Aseprite loads the
+// palette of such a file as a preset, and it may also be opened as a sprite.
+func EncodeAseprite(w io.Writer, colors []color.RGBA) error {
+
+	var chunks [][]byte
+
+	var layer bytes.Buffer
+	binary.Write(&layer, binary.LittleEndian, asepriteLayer{Flags: 3, Opacity: 0xFF})
+	binary.Write(&layer, binary.LittleEndian, uint16(len("Background")))
+	layer.WriteString("Background")
+	chunks = append(chunks, asepriteChunk(asepriteLayerChunk, layer.Bytes()))
+
+	// A palette chunk always holds at least one entry
+	if len(colors) > 0 {
+		var palette bytes.Buffer
+		binary.Write(&palette, binary.LittleEndian, asepritePalette{
+			Size: uint32(len(colors)),
+			Last: uint32(len(colors) - 1),
+		})
+		for _, clr := range colors {
+			binary.Write(&palette, binary.LittleEndian, asepriteEntry{0, clr.R, clr.G, clr.B, clr.A})
+		}
+		chunks = append(chunks, asepriteChunk(asepritePaletteChunk, palette.Bytes()))
+	}
+
+	frame := asepriteFrame{Size: 16, Magic: 0xF1FA, OldChunks: uint16(len(chunks)), Duration: 100, Chunks: uint32(len(chunks))}
+	for _, chunk := range chunks {
+		frame.Size += uint32(len(chunk))
+	}
+
+	// The header counts at most 65535 colors, with 0 meaning 256
+	count := len(colors)
+	if count > 0xFFFF || count == 256 {
+		count = 0
+	}
+
+	header := asepriteHeader{
+		Size:        128 + frame.Size,
+		Magic:       0xA5E0,
+		Frames:      1,
+		Width:       1,
+		Height:      1,
+		Depth:       32,
+		Flags:       1,
+		Speed:       100,
+		Colors:      uint16(count),
+		PixelWidth:  1,
+		PixelHeight: 1,
+		GridWidth:   16,
+		GridHeight:  16,
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, frame)
+	for _, chunk := range chunks {
+		buf.Write(chunk)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// asepriteChunk returns a chunk of the given type and body, prefixed with its
+// size and type.
+func asepriteChunk(kind uint16, body []byte) []byte {
+	chunk := make([]byte, 6, 6+len(body))
+	binary.LittleEndian.PutUint32(chunk, uint32(6+len(body)))
+	binary.LittleEndian.PutUint16(chunk[4:], kind)
+	return append(chunk, body...)
+}
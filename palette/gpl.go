@@ -0,0 +1,73 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+// EncodeGPL writes the given palette in the GIMP .gpl format, naming each
+// color by its hex code.
+func EncodeGPL(w io.Writer, colors []color.RGBA) error {
+	if _, err := fmt.Fprint(w, "GIMP Palette\nName: quantize\n#\n"); err != nil {
+		return err
+	}
+
+	for _, clr := range colors {
+		if _, err := fmt.Fprintf(w, "%3d %3d %3d\t%s\n", clr.R, clr.G, clr.B, quantize.Hex(clr)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeGPL reads a palette in the GIMP .gpl format. The palette name, column
+// count, comments, and color names are ignored.
+func DecodeGPL(r io.Reader) ([]color.RGBA, error) {
+	text, err := lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(text) < 1 || text[0] != "GIMP Palette" {
+		return nil, errors.New("gpl: invalid header")
+	}
+
+	colors := []color.RGBA{}
+
+	for index, line := range text[1:] {
+		if line == "" || strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, "Name:") || strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+
+		// Any fields after the components form the color name
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("gpl: line %d: expected 3 components", index+2)
+		}
+
+		var components [3]uint8
+		for channel, field := range fields[:3] {
+			value, err := strconv.ParseUint(field, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("gpl: line %d: invalid component %q", index+2, field)
+			}
+			components[channel] = uint8(value)
+		}
+
+		colors = append(colors, color.RGBA{components[0], components[1], components[2], 0xFF})
+	}
+
+	return colors, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EncodeJASC writes the given palette in the JASC-PAL format used by Paint
+// Shop Pro, with the CRLF line endings that format expects. Aseprite reads and
+// writes .pal files in this format.
+func EncodeJASC(w io.Writer, colors []color.RGBA) error {
+	if _, err := fmt.Fprintf(w, "JASC-PAL\r\n0100\r\n%d\r\n", len(colors)); err != nil {
+		return err
+	}
+
+	for _, clr := range colors {
+		if _, err := fmt.Fprintf(w, "%d %d %d\r\n", clr.R, clr.G, clr.B); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeJASC reads a palette in the JASC-PAL format, with either CRLF or LF
+// line endings.
+func DecodeJASC(r io.Reader) ([]color.RGBA, error) {
+	text, err := lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore any trailing blank lines
+	for len(text) > 0 && text[len(text)-1] == "" {
+		text = text[:len(text)-1]
+	}
+
+	if len(text) < 3 || text[0] != "JASC-PAL" || text[1] != "0100" {
+		return nil, errors.New("jasc-pal: invalid header")
+	}
+
+	count, err := strconv.Atoi(text[2])
+	if err != nil || count < 0 || count != len(text)-3 {
+		return nil, fmt.Errorf("jasc-pal: invalid color count %q", text[2])
+	}
+
+	colors := make([]color.RGBA, count)
+
+	for index, line := range text[3:] {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("jasc-pal: line %d: expected 3 components", index+4)
+		}
+
+		var components [3]uint8
+		for channel, field := range fields {
+			value, err := strconv.ParseUint(field, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("jasc-pal: line %d: invalid component %q", index+4, field)
+			}
+			components[channel] = uint8(value)
+		}
+
+		colors[index] = color.RGBA{components[0], components[1], components[2], 0xFF}
+	}
+
+	return colors, nil
+}
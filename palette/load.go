@@ -0,0 +1,58 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+// decoders maps the extension of every supported palette file to its decoder.
+var decoders = map[string]func(io.Reader) ([]color.RGBA, error){
+	".act": DecodeACT,
+	".gpl": DecodeGPL,
+	".hex": DecodeLospec,
+	".pal": DecodeJASC,
+}
+
+// Load takes in either a comma separated list of hex colors, such as
+// "#112233,#445566", or the path to an .act, .gpl, .hex, or .pal palette file,
+// and returns the palette that it describes.
+func Load(spec string) ([]color.RGBA, error) {
+
+	if strings.HasPrefix(spec, "#") {
+		fields := strings.Split(spec, ",")
+		colors := make([]color.RGBA, len(fields))
+
+		for index, field := range fields {
+			clr, err := quantize.ParseHex(strings.TrimSpace(field))
+			if err != nil {
+				return nil, err
+			}
+			colors[index] = clr
+		}
+
+		return colors, nil
+	}
+
+	decode, found := decoders[strings.ToLower(filepath.Ext(spec))]
+	if !found {
+		return nil, fmt.Errorf("%s: unsupported palette format", spec)
+	}
+
+	file, err := os.Open(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return decode(file)
+}
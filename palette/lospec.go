@@ -0,0 +1,57 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/joshdk/quantize"
+)
+
+// EncodeLospec writes the given palette in the Lospec .hex format, which lists
+// one lowercase RRGGBB color per line.
+func EncodeLospec(w io.Writer, colors []color.RGBA) error {
+	for _, clr := range colors {
+		if _, err := fmt.Fprintf(w, "%02x%02x%02x\n", clr.R, clr.G, clr.B); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeLospec reads a palette in the Lospec .hex format. Blank lines are
+// ignored, and colors may optionally be prefixed with a "#".
+func DecodeLospec(r io.Reader) ([]color.RGBA, error) {
+	text, err := lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := []color.RGBA{}
+
+	for index, line := range text {
+		if line == "" {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "#")
+		if len(line) != 6 {
+			return nil, fmt.Errorf("lospec: line %d: invalid color %q", index+1, line)
+		}
+
+		clr, err := quantize.ParseHex("#" + line)
+		if err != nil {
+			return nil, fmt.Errorf("lospec: line %d: invalid color %q", index+1, line)
+		}
+
+		colors = append(colors, clr)
+	}
+
+	return colors, nil
+}
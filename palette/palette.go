@@ -0,0 +1,26 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package palette reads and writes palettes in the file formats used to share
+// them between tools, such as the Lospec hex list format and JASC-PAL.
+package palette
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// lines reads every line from the given reader, with surrounding whitespace
+// (including any carriage returns) removed.
+func lines(r io.Reader) ([]string, error) {
+	var result []string
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		result = append(result, strings.TrimSpace(scanner.Text()))
+	}
+
+	return result, scanner.Err()
+}
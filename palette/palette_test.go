@@ -0,0 +1,391 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColors = []color.RGBA{
+	{0x13, 0x25, 0x5c, 0xFF},
+	{0xFF, 0xFF, 0xFF, 0xFF},
+	{0x00, 0x80, 0x0a, 0xFF},
+}
+
+// repeat returns a palette of the given color repeated n times.
+func repeat(clr color.RGBA, n int) []color.RGBA {
+	colors := make([]color.RGBA, n)
+	for index := range colors {
+		colors[index] = clr
+	}
+	return colors
+}
+
+func TestRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		encode  func(io.Writer, []color.RGBA) error
+		decode  func(io.Reader) ([]color.RGBA, error)
+		encoded string
+	}{
+		{
+			title:   "lospec",
+			encode:  EncodeLospec,
+			decode:  DecodeLospec,
+			encoded: "13255c\nffffff\n00800a\n",
+		},
+		{
+			title:   "jasc-pal",
+			encode:  EncodeJASC,
+			decode:  DecodeJASC,
+			encoded: "JASC-PAL\r\n0100\r\n3\r\n19 37 92\r\n255 255 255\r\n0 128 10\r\n",
+		},
+		{
+			title:   "gpl",
+			encode:  EncodeGPL,
+			decode:  DecodeGPL,
+			encoded: "GIMP Palette\nName: quantize\n#\n 19  37  92\t#13255C\n255 255 255\t#FFFFFF\n  0 128  10\t#00800A\n",
+		},
+		{
+			title:   "act",
+			encode:  EncodeACT,
+			decode:  DecodeACT,
+			encoded: "\x13\x25\x5c\xff\xff\xff\x00\x80\x0a" + strings.Repeat("\x00", 759) + "\x00\x03\xff\xff",
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			require.Nil(t, test.encode(&buf, testColors))
+			assert.Equal(t, test.encoded, buf.String())
+
+			colors, err := test.decode(strings.NewReader(test.encoded))
+			require.Nil(t, err)
+			assert.Equal(t, testColors, colors)
+
+			// An empty palette should also survive
+			buf.Reset()
+			require.Nil(t, test.encode(&buf, []color.RGBA{}))
+
+			colors, err = test.decode(&buf)
+			require.Nil(t, err)
+			assert.Equal(t, []color.RGBA{}, colors)
+
+		})
+	}
+
+}
+
+func TestDecode(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		decode func(io.Reader) ([]color.RGBA, error)
+		data   string
+		colors []color.RGBA
+		valid  bool
+	}{
+		{
+			title:  "lospec with prefixes and blank lines",
+			decode: DecodeLospec,
+			data:   "#13255C\r\n\n  ffffff  \n",
+			colors: testColors[:2],
+			valid:  true,
+		},
+		{
+			title:  "lospec short color",
+			decode: DecodeLospec,
+			data:   "fff\n",
+		},
+		{
+			title:  "lospec invalid color",
+			decode: DecodeLospec,
+			data:   "13255c\nzzzzzz\n",
+		},
+		{
+			title:  "jasc-pal with lf and trailing lines",
+			decode: DecodeJASC,
+			data:   "JASC-PAL\n0100\n2\n19 37 92\n255  255\t255\n\n",
+			colors: testColors[:2],
+			valid:  true,
+		},
+		{
+			title:  "jasc-pal invalid header",
+			decode: DecodeJASC,
+			data:   "GIMP Palette\n0100\n0\n",
+		},
+		{
+			title:  "jasc-pal mismatched count",
+			decode: DecodeJASC,
+			data:   "JASC-PAL\n0100\n2\n19 37 92\n",
+		},
+		{
+			title:  "jasc-pal component out of range",
+			decode: DecodeJASC,
+			data:   "JASC-PAL\n0100\n1\n19 37 256\n",
+		},
+		{
+			title:  "jasc-pal missing component",
+			decode: DecodeJASC,
+			data:   "JASC-PAL\n0100\n1\n19 37\n",
+		},
+		{
+			title:  "gpl with columns and unnamed colors",
+			decode: DecodeGPL,
+			data:   "GIMP Palette\nName: Test\nColumns: 4\n# comment\n19 37 92 Navy Blue\n255 255 255\n",
+			colors: testColors[:2],
+			valid:  true,
+		},
+		{
+			title:  "act without a color count",
+			decode: DecodeACT,
+			data:   strings.Repeat("\xff", 768),
+			colors: repeat(color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, 256),
+			valid:  true,
+		},
+		{
+			title:  "act truncated",
+			decode: DecodeACT,
+			data:   "\x13\x25\x5c",
+		},
+		{
+			title:  "act color count out of range",
+			decode: DecodeACT,
+			data:   strings.Repeat("\x00", 768) + "\x01\x01\xff\xff",
+		},
+		{
+			title:  "gpl invalid header",
+			decode: DecodeGPL,
+			data:   "JASC-PAL\n",
+		},
+		{
+			title:  "gpl invalid component",
+			decode: DecodeGPL,
+			data:   "GIMP Palette\n19 -37 92\n",
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			colors, err := test.decode(strings.NewReader(test.data))
+
+			if !test.valid {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.colors, colors)
+
+		})
+	}
+
+}
+
+func TestLoad(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "palette")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"test.gpl", "test.HEX", "test.pal", "test.act"} {
+		var buf bytes.Buffer
+		switch filepath.Ext(name) {
+		case ".gpl":
+			require.Nil(t, EncodeGPL(&buf, testColors))
+		case ".HEX":
+			require.Nil(t, EncodeLospec(&buf, testColors))
+		case ".pal":
+			require.Nil(t, EncodeJASC(&buf, testColors))
+		case ".act":
+			require.Nil(t, EncodeACT(&buf, testColors))
+		}
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0644))
+	}
+
+	tests := []struct {
+		title  string
+		spec   string
+		colors []color.RGBA
+		valid  bool
+	}{
+		{
+			title:  "hex list",
+			spec:   "#13255C, #FFF,#00800a",
+			colors: testColors,
+			valid:  true,
+		},
+		{
+			title: "invalid hex list",
+			spec:  "#13255C,13255C",
+		},
+		{
+			title:  "gpl file",
+			spec:   filepath.Join(dir, "test.gpl"),
+			colors: testColors,
+			valid:  true,
+		},
+		{
+			title:  "lospec file",
+			spec:   filepath.Join(dir, "test.HEX"),
+			colors: testColors,
+			valid:  true,
+		},
+		{
+			title:  "jasc-pal file",
+			spec:   filepath.Join(dir, "test.pal"),
+			colors: testColors,
+			valid:  true,
+		},
+		{
+			title:  "act file",
+			spec:   filepath.Join(dir, "test.act"),
+			colors: testColors,
+			valid:  true,
+		},
+		{
+			title: "missing file",
+			spec:  filepath.Join(dir, "missing.gpl"),
+		},
+		{
+			title: "unsupported format",
+			spec:  filepath.Join(dir, "test.aco"),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			colors, err := Load(test.spec)
+
+			if !test.valid {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.colors, colors)
+
+		})
+	}
+
+}
+
+func TestEncodeAseprite(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		colors []color.RGBA
+		count  uint16
+	}{
+		{
+			title:  "palette",
+			colors: testColors,
+			count:  3,
+		},
+		{
+			title:  "translucent color",
+			colors: []color.RGBA{{0x11, 0x22, 0x33, 0x44}},
+			count:  1,
+		},
+		{
+			title:  "empty palette",
+			colors: []color.RGBA{},
+			count:  0,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			require.Nil(t, EncodeAseprite(&buf, test.colors))
+			data := buf.Bytes()
+
+			var header asepriteHeader
+			require.Equal(t, 128, binary.Size(header))
+			require.Nil(t, binary.Read(bytes.NewReader(data), binary.LittleEndian, &header))
+
+			assert.Equal(t, uint16(0xA5E0), header.Magic)
+			assert.Equal(t, uint32(len(data)), header.Size)
+			assert.Equal(t, test.count, header.Colors)
+
+			var frame asepriteFrame
+			require.Nil(t, binary.Read(bytes.NewReader(data[128:]), binary.LittleEndian, &frame))
+
+			assert.Equal(t, uint16(0xF1FA), frame.Magic)
+			assert.Equal(t, uint32(len(data)-128), frame.Size)
+
+			// Walk the chunks, collecting the palette entries
+			colors := []color.RGBA{}
+			chunks := data[128+16:]
+
+			for chunk := uint32(0); chunk < frame.Chunks; chunk++ {
+				size := binary.LittleEndian.Uint32(chunks)
+				kind := binary.LittleEndian.Uint16(chunks[4:])
+
+				if kind == asepritePaletteChunk {
+					var palette asepritePalette
+					require.Nil(t, binary.Read(bytes.NewReader(chunks[6:]), binary.LittleEndian, &palette))
+
+					entries := make([]asepriteEntry, palette.Size)
+					require.Nil(t, binary.Read(bytes.NewReader(chunks[6+20:]), binary.LittleEndian, entries))
+
+					for _, entry := range entries {
+						colors = append(colors, color.RGBA{entry.R, entry.G, entry.B, entry.A})
+					}
+				}
+
+				chunks = chunks[size:]
+			}
+
+			assert.Empty(t, chunks)
+			assert.Equal(t, test.colors, colors)
+
+		})
+	}
+
+}
+
+func TestEncodePNG(t *testing.T) {
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodePNG(&buf, testColors))
+
+	img, err := png.Decode(&buf)
+	require.Nil(t, err)
+	require.Equal(t, image.Rect(0, 0, len(testColors), 1), img.Bounds())
+
+	for index, clr := range testColors {
+		assert.Equal(t, clr, color.RGBAModel.Convert(img.At(index, 0)))
+	}
+
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package palette
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// EncodePNG writes the given palette as a PNG strip with one pixel per color,
+// the layout that Lospec publishes palettes in.
+func EncodePNG(w io.Writer, colors []color.RGBA) error {
+	img := image.NewRGBA(image.Rect(0, 0, len(colors), 1))
+
+	for index, clr := range colors {
+		img.SetRGBA(index, 0, clr)
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,247 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaletteRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		palette Palette
+		json    string
+	}{
+		{
+			title:   "empty palette",
+			palette: Palette{},
+			json:    `[]`,
+		},
+		{
+			title: "single color",
+			palette: Palette{
+				{0x13, 0x25, 0x5c, 0xFF},
+			},
+			json: `["#13255C"]`,
+		},
+		{
+			title: "translucent color",
+			palette: Palette{
+				{0x13, 0x25, 0x5c, 0x80},
+			},
+			json: `["#13255C80"]`,
+		},
+		{
+			title: "multiple colors",
+			palette: Palette{
+				{0, 0, 0, 0xFF},
+				{255, 255, 255, 0xFF},
+				{105, 32, 165, 0xFF}, // random values
+			},
+			json: `["#000000","#FFFFFF","#6920A5"]`,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			data, err := json.Marshal(test.palette)
+			require.Nil(t, err)
+			assert.Equal(t, test.json, string(data))
+
+			var fromJSON Palette
+			require.Nil(t, json.Unmarshal(data, &fromJSON))
+			assert.Equal(t, test.palette, fromJSON)
+
+			data, err = test.palette.MarshalBinary()
+			require.Nil(t, err)
+			assert.Equal(t, 4+1+1+4*len(test.palette), len(data))
+
+			var fromBinary Palette
+			require.Nil(t, fromBinary.UnmarshalBinary(data))
+			assert.Equal(t, test.palette, fromBinary)
+
+		})
+	}
+
+}
+
+func TestPaletteInvalid(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		json   string
+		binary []byte
+	}{
+		{
+			title:  "empty data",
+			json:   ``,
+			binary: []byte{},
+		},
+		{
+			title:  "bad header",
+			json:   `{}`,
+			binary: []byte("QPEL\x01\x00"),
+		},
+		{
+			title:  "missing prefix",
+			json:   `["13255C"]`,
+			binary: []byte("QPAL\x02\x00"),
+		},
+		{
+			title:  "bad digits",
+			json:   `["#13255G"]`,
+			binary: []byte("QPAL\x01"),
+		},
+		{
+			title:  "short color",
+			json:   `["#FFFFF"]`,
+			binary: []byte("QPAL\x01\x01\xFF\xFF\xFF"),
+		},
+		{
+			title:  "trailing data",
+			json:   `["#FFFFFF"]]`,
+			binary: []byte("QPAL\x01\x00\xFF"),
+		},
+		{
+			title:  "overflowing count",
+			json:   `[1]`,
+			binary: []byte("QPAL\x01\x80\x80\x80\x80\x80\x80\x80\x80\x40\xFF\xFF\xFF\xFF"),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var palette Palette
+
+			assert.NotNil(t, json.Unmarshal([]byte(test.json), &palette))
+			assert.NotNil(t, palette.UnmarshalBinary(test.binary))
+
+		})
+	}
+
+}
+
+func TestResultRoundTrip(t *testing.T) {
+
+	exemplar := image.Pt(3, -7)
+
+	tests := []struct {
+		title  string
+		result Result
+	}{
+		{
+			title:  "empty result",
+			result: Result{Colors: []Swatch{}},
+		},
+		{
+			title: "single swatch",
+			result: Result{
+				Colors: []Swatch{
+					newSwatch(color.RGBA{0x13, 0x25, 0x5c, 0xFF}, 100, 1),
+				},
+				Pixels: 100,
+			},
+		},
+		{
+			title: "multiple swatches",
+			result: Result{
+				Colors: []Swatch{
+					newSwatch(color.RGBA{0, 0, 0, 0xFF}, 300, 0.75),
+					newSwatch(color.RGBA{255, 255, 255, 0xFF}, 75, 0.1875),
+					newSwatch(color.RGBA{105, 32, 165, 0xFF}, 25, 0.0625), // random values
+				},
+				Pixels: 400,
+			},
+		},
+		{
+			title: "exemplar",
+			result: Result{
+				Colors: []Swatch{
+					func() Swatch {
+						swatch := newSwatch(color.RGBA{105, 32, 165, 0xFF}, 1, 1)
+						swatch.Exemplar = &exemplar
+						return swatch
+					}(),
+				},
+				Pixels: 1,
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			data, err := test.result.MarshalBinary()
+			require.Nil(t, err)
+
+			var fromBinary Result
+			require.Nil(t, fromBinary.UnmarshalBinary(data))
+			assert.Equal(t, test.result, fromBinary)
+
+		})
+	}
+
+}
+
+func TestResultInvalid(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		binary []byte
+	}{
+		{
+			title:  "empty data",
+			binary: []byte{},
+		},
+		{
+			title:  "bad header",
+			binary: []byte("QPAL\x01\x00\x00"),
+		},
+		{
+			title:  "bad version",
+			binary: []byte("QRES\x02\x00\x00"),
+		},
+		{
+			title:  "short swatch",
+			binary: []byte("QRES\x01\x01\x01\xFF\xFF\xFF\xFF\x01"),
+		},
+		{
+			title:  "overflowing count",
+			binary: []byte("QRES\x01\x00\x80\x80\x80\x80\x80\x80\x80\x80\x40"),
+		},
+		{
+			title:  "trailing data",
+			binary: []byte("QRES\x01\x00\x00\xFF"),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var result Result
+			assert.NotNil(t, result.UnmarshalBinary(test.binary))
+
+		})
+	}
+
+}
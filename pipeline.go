@@ -0,0 +1,72 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// RemapNRGBA replaces the color of every pixel of the given image with the
+// nearest palette color, in place, keeping the alpha of every pixel. Pixels
+// are compared by their non-premultiplied color. Returns the same image, so
+// that it can be chained with imaging libraries that pass *image.NRGBA values
+// between their operations. An empty palette leaves the image unchanged.
+func RemapNRGBA(img *image.NRGBA, colors []color.RGBA) *image.NRGBA {
+
+	if len(colors) == 0 {
+		return img
+	}
+
+	// Pixels of the same color are common, so each is only matched once
+	matches := map[[3]uint8]color.RGBA{}
+
+	rect := img.Bounds()
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		start := img.PixOffset(rect.Min.X, y)
+		row := img.Pix[start : start+rect.Dx()*4]
+
+		for offset := 0; offset < len(row); offset += 4 {
+			key := [3]uint8{row[offset], row[offset+1], row[offset+2]}
+
+			match, found := matches[key]
+			if !found {
+				index, _ := nearest(colors, color.RGBA{key[0], key[1], key[2], 0xFF})
+				match = colors[index]
+				matches[key] = match
+			}
+
+			row[offset], row[offset+1], row[offset+2] = match.R, match.G, match.B
+		}
+	}
+
+	return img
+}
+
+// Stage returns a processing stage, in the style of the operations of NRGBA
+// imaging libraries, that quantizes an image to the given number of levels and
+// remaps it to the resulting palette. The palette is found as by ImageNRGBA,
+// and every pixel keeps its alpha. An *image.NRGBA is remapped in place
+// without being copied, while any other image is first converted into a new
+// one. Levels outside of [0, MaxLevels] are clamped.
+func Stage(levels int) func(image.Image) *image.NRGBA {
+	return func(img image.Image) *image.NRGBA {
+
+		nrgba, ok := img.(*image.NRGBA)
+		if !ok {
+			nrgba = image.NewNRGBA(img.Bounds())
+			draw.Draw(nrgba, nrgba.Bounds(), img, img.Bounds().Min, draw.Src)
+		}
+
+		palette := ImageNRGBA(nrgba, levels)
+		colors := make([]color.RGBA, len(palette))
+		for index, clr := range palette {
+			colors[index] = color.RGBA{clr.R, clr.G, clr.B, 0xFF}
+		}
+
+		return RemapNRGBA(nrgba, colors)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gradientNRGBA returns an image with every combination of a few colors and
+// alphas, offset from the origin.
+func gradientNRGBA() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(3, 5, 19, 21))
+	for x := 3; x < 19; x++ {
+		for y := 5; y < 21; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 13), uint8(y * 11), uint8(x * y), uint8(x*y*7 + 40)})
+		}
+	}
+	return img
+}
+
+func TestExtractNRGBA(t *testing.T) {
+
+	img := gradientNRGBA()
+	rect := image.Rect(4, 6, 12, 30)
+
+	// The fast paths match the generic extraction exactly
+	assert.Equal(t, extract(nil, opaqueImage{img}, rect), extract(nil, img, rect))
+	assert.Equal(t, extractNRGBA(nil, opaqueImage{img}, rect), extractNRGBA(nil, img, rect))
+}
+
+func TestRemapNRGBA(t *testing.T) {
+
+	img := image.NewNRGBA(image.Rect(1, 1, 3, 2))
+	img.SetNRGBA(1, 1, color.NRGBA{250, 10, 10, 0x80})
+	img.SetNRGBA(2, 1, color.NRGBA{10, 10, 240, 0xFF})
+
+	remapped := RemapNRGBA(img, []color.RGBA{red, blue})
+
+	assert.True(t, remapped == img)
+	assert.Equal(t, color.NRGBA{255, 0, 0, 0x80}, img.NRGBAAt(1, 1))
+	assert.Equal(t, color.NRGBA{0, 0, 255, 0xFF}, img.NRGBAAt(2, 1))
+
+	// An empty palette leaves the image unchanged
+	assert.Equal(t, color.NRGBA{255, 0, 0, 0x80}, RemapNRGBA(img, nil).NRGBAAt(1, 1))
+}
+
+func TestStage(t *testing.T) {
+
+	stage := Stage(1)
+
+	// NRGBA images are remapped in place
+	img := gradientNRGBA()
+	alphas := make([]uint8, 0, len(img.Pix)/4)
+	for offset := 3; offset < len(img.Pix); offset += 4 {
+		alphas = append(alphas, img.Pix[offset])
+	}
+
+	result := stage(img)
+	assert.True(t, result == img)
+
+	colors := map[[3]uint8]bool{}
+	for offset := 0; offset < len(result.Pix); offset += 4 {
+		colors[[3]uint8{result.Pix[offset], result.Pix[offset+1], result.Pix[offset+2]}] = true
+		assert.Equal(t, alphas[offset/4], result.Pix[offset+3])
+	}
+	assert.True(t, len(colors) <= 2)
+
+	// Other images are converted first, leaving the original unchanged
+	rgba := testImage(4, 4, func(x int, _ int) color.RGBA {
+		if x < 2 {
+			return red
+		}
+		return color.RGBA{0, 0, 250, 0xFF}
+	})
+
+	converted := stage(rgba)
+	assert.Equal(t, rgba.Bounds(), converted.Bounds())
+	assert.Equal(t, color.NRGBA{255, 0, 0, 0xFF}, converted.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{0, 0, 250, 0xFF}, converted.NRGBAAt(3, 0))
+	assert.Equal(t, color.RGBA{0, 0, 250, 0xFF}, rgba.At(3, 0))
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/joshdk/quantize"
+)
+
+// blurHashLimit is the largest dimension an image is downsampled to before
+// computing its BlurHash, which keeps the cost independent of image size.
+const blurHashLimit = 64
+
+// base83 is the alphabet used by the BlurHash encoding.
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// ErrInvalidComponents is returned when the number of BlurHash components is
+// not within [1, 9].
+var ErrInvalidComponents = errors.New("blurhash components must be between 1 and 9")
+
+// BlurHash takes in an image, and returns its BlurHash string using the given
+// number of horizontal & vertical components.
+func BlurHash(img image.Image, xComponents int, yComponents int) (string, error) {
+
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", ErrInvalidComponents
+	}
+
+	width, height, pixels := downsample(img, blurHashLimit)
+
+	if width == 0 || height == 0 {
+		return "", errors.New("image has no pixels")
+	}
+
+	// Convert every pixel into linear light up front
+	linear := make([][3]float64, len(pixels))
+	for index, pixel := range pixels {
+		linear[index] = [3]float64{
+			quantize.Linearize(pixel.R),
+			quantize.Linearize(pixel.G),
+			quantize.Linearize(pixel.B),
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+
+			normalization := 2.0
+			if i == 0 && j == 0 {
+				normalization = 1.0
+			}
+
+			var factor [3]float64
+
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := normalization *
+						math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+
+					pixel := linear[x+y*width]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+
+			scale := 1 / float64(width*height)
+			factors = append(factors, [3]float64{
+				factor[0] * scale,
+				factor[1] * scale,
+				factor[2] * scale,
+			})
+		}
+	}
+
+	dc, ac := factors[0], factors[1:]
+
+	hash := encode83((xComponents-1)+(yComponents-1)*9, 1)
+
+	// Encode the largest AC magnitude, used to normalize all AC components
+	maximum := 1.0
+	if len(ac) > 0 {
+		var actual float64
+		for _, factor := range ac {
+			actual = math.Max(actual, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+
+		quantized := int(math.Max(0, math.Min(82, math.Floor(actual*166-0.5))))
+		maximum = float64(quantized+1) / 166
+		hash += encode83(quantized, 1)
+	} else {
+		hash += encode83(0, 1)
+	}
+
+	srgb := quantize.Delinearize
+	hash += encode83(int(srgb(dc[0]))<<16|int(srgb(dc[1]))<<8|int(srgb(dc[2])), 4)
+
+	for _, factor := range ac {
+		quantR := quantizeAC(factor[0] / maximum)
+		quantG := quantizeAC(factor[1] / maximum)
+		quantB := quantizeAC(factor[2] / maximum)
+
+		hash += encode83(quantR*19*19+quantG*19+quantB, 2)
+	}
+
+	return hash, nil
+}
+
+// quantizeAC maps a normalized AC component onto the range [0, 18].
+func quantizeAC(value float64) int {
+	signed := math.Copysign(math.Pow(math.Abs(value), 0.5), value)
+
+	return int(math.Max(0, math.Min(18, math.Floor(signed*9+9.5))))
+}
+
+// encode83 renders the given value as a fixed length base 83 string.
+func encode83(value int, length int) string {
+	digits := make([]byte, length)
+
+	for index := length - 1; index >= 0; index-- {
+		digits[index] = base83[value%83]
+		value /= 83
+	}
+
+	return string(digits)
+}
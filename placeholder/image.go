@@ -0,0 +1,105 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/joshdk/quantize"
+)
+
+// Style is the way a placeholder image is rendered.
+type Style int
+
+const (
+	// Solid fills the placeholder with the average color of the image.
+	Solid Style = iota
+
+	// Gradient blends vertically from the average color of the top half of
+	// the image to that of the bottom half.
+	Gradient
+
+	// Mosaic blends smoothly between the average colors of a 4x4 grid of
+	// tiles laid over the image.
+	Mosaic
+)
+
+// mosaicSize is the number of columns and rows of tiles in a Mosaic.
+const mosaicSize = 4
+
+// ErrInvalidSize is returned when a placeholder image would have no pixels.
+var ErrInvalidSize = errors.New("placeholder size must be at least 1x1")
+
+// Image takes in an image, and returns a placeholder image of the given size
+// rendered in the given style, suitable for encoding as a tiny PNG.
+func Image(img image.Image, width int, height int, style Style) (*image.RGBA, error) {
+
+	if width < 1 || height < 1 {
+		return nil, ErrInvalidSize
+	}
+
+	if img.Bounds().Empty() {
+		return nil, errors.New("image has no pixels")
+	}
+
+	var cols, rows int
+	switch style {
+	case Solid:
+		cols, rows = 1, 1
+	case Gradient:
+		cols, rows = 1, 2
+	case Mosaic:
+		cols, rows = mosaicSize, mosaicSize
+	default:
+		return nil, errors.New("unknown placeholder style")
+	}
+
+	tiles := make([]color.RGBA, 0, cols*rows)
+	for _, palette := range quantize.Grid(img, cols, rows, 0) {
+		tiles = append(tiles, palette[0])
+	}
+
+	placeholder := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		row, fy := position(y, height, rows)
+
+		for x := 0; x < width; x++ {
+			col, fx := position(x, width, cols)
+
+			// Blend bilinearly between the centers of the nearest tiles
+			next := min(col+1, cols-1)
+			top := quantize.Mix(tiles[row*cols+col], tiles[row*cols+next], fx)
+			bottom := top
+			if below := min(row+1, rows-1); below != row {
+				bottom = quantize.Mix(tiles[below*cols+col], tiles[below*cols+next], fx)
+			}
+
+			placeholder.SetRGBA(x, y, quantize.Mix(top, bottom, fy))
+		}
+	}
+
+	return placeholder, nil
+}
+
+// position returns the tile whose center precedes the center of the given
+// pixel, and how far the pixel lies towards the center of the next tile.
+func position(pixel int, pixels int, tiles int) (int, float64) {
+	center := (float64(pixel)+0.5)/float64(pixels)*float64(tiles) - 0.5
+	center = math.Max(0, math.Min(center, float64(tiles-1)))
+
+	tile := int(center)
+	return tile, center - float64(tile)
+}
+
+func min(first int, second int) int {
+	if first < second {
+		return first
+	}
+	return second
+}
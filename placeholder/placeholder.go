@@ -0,0 +1,40 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package placeholder generates compact BlurHash and ThumbHash strings, and
+// tiny placeholder images, which can be rendered as blurry stand-ins while the
+// full image is still loading.
+package placeholder
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/joshdk/quantize"
+)
+
+// downsample takes in an image, and returns its non-premultiplied pixels in
+// row-major order, box filtered by quantize.Downsample so that neither
+// dimension exceeds the given limit.
+func downsample(img image.Image, limit int) (int, int, []color.NRGBA) {
+
+	small := quantize.Downsample(img, limit)
+	width, height := small.Rect.Dx(), small.Rect.Dy()
+
+	pixels := make([]color.NRGBA, 0, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels = append(pixels, small.NRGBAAt(x, y))
+		}
+	}
+
+	return width, height, pixels
+}
+
+func max(first int, second int) int {
+	if first > second {
+		return first
+	}
+	return second
+}
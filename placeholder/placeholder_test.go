@@ -0,0 +1,394 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package placeholder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// solid builds an image of the given size filled with a single color.
+func solid(width int, height int, clr color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, clr)
+		}
+	}
+
+	return img
+}
+
+// pattern builds an image of the given size with an irregular pattern of
+// colors, so that no hash component lies near a rounding boundary. Every third
+// pixel is partially transparent if translucent is set.
+func pattern(width int, height int, translucent bool) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			alpha := uint8(0xFF)
+			if translucent && (x+y)%3 == 0 {
+				alpha = 96
+			}
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x*37 + y*91), uint8(x*x*13 + y*7), uint8(x*y*29 + 50), alpha})
+		}
+	}
+
+	return img
+}
+
+func TestDownsample(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		img    image.Image
+		limit  int
+		width  int
+		height int
+	}{
+		{
+			title:  "already fits",
+			img:    solid(10, 5, color.White),
+			limit:  10,
+			width:  10,
+			height: 5,
+		},
+		{
+			title:  "landscape",
+			img:    solid(200, 50, color.White),
+			limit:  100,
+			width:  100,
+			height: 25,
+		},
+		{
+			title:  "portrait",
+			img:    solid(50, 200, color.White),
+			limit:  100,
+			width:  25,
+			height: 100,
+		},
+		{
+			title:  "extreme aspect ratio",
+			img:    solid(1000, 1, color.White),
+			limit:  100,
+			width:  100,
+			height: 1,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			width, height, pixels := downsample(test.img, test.limit)
+
+			assert.Equal(t, test.width, width)
+			assert.Equal(t, test.height, height)
+			require.Equal(t, width*height, len(pixels))
+
+			for _, pixel := range pixels {
+				assert.Equal(t, color.NRGBA{255, 255, 255, 255}, pixel)
+			}
+
+		})
+	}
+
+}
+
+func TestBlurHash(t *testing.T) {
+
+	tests := []struct {
+		title string
+		img   image.Image
+		x     int
+		y     int
+		hash  string
+		valid bool
+	}{
+		{
+			title: "solid white",
+			img:   solid(32, 32, color.White),
+			x:     4,
+			y:     3,
+			hash:  "L9TSUA~qfQ~q~qoffQoffQfQfQfQ",
+			valid: true,
+		},
+		{
+			title: "pattern",
+			img:   pattern(7, 5, false),
+			x:     4,
+			y:     3,
+			hash:  "LbGbt}Y;Mgxo-nNHR%xFV;X2J:Ny",
+			valid: true,
+		},
+		{
+			title: "pattern single component",
+			img:   pattern(7, 5, false),
+			x:     1,
+			y:     1,
+			hash:  "00Gbt}",
+			valid: true,
+		},
+		{
+			title: "solid black single component",
+			img:   solid(32, 32, color.Black),
+			x:     1,
+			y:     1,
+			hash:  "000000",
+			valid: true,
+		},
+		{
+			title: "too few components",
+			img:   solid(32, 32, color.White),
+			x:     0,
+			y:     3,
+		},
+		{
+			title: "too many components",
+			img:   solid(32, 32, color.White),
+			x:     4,
+			y:     10,
+		},
+		{
+			title: "empty image",
+			img:   solid(0, 0, color.White),
+			x:     4,
+			y:     3,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			hash, err := BlurHash(test.img, test.x, test.y)
+
+			if !test.valid {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.hash, hash)
+
+		})
+	}
+
+}
+
+func TestThumbHash(t *testing.T) {
+
+	// The AC components of a solid image are rounding noise, so only the
+	// header of its hash is compared, while patterns are compared in full
+	// against the reference implementation
+	tests := []struct {
+		title  string
+		img    image.Image
+		header []byte
+		length int
+	}{
+		{
+			title:  "solid white",
+			img:    solid(32, 32, color.White),
+			header: []byte{0x3F, 0x08, 0x02, 0x07, 0x00},
+			length: 24,
+		},
+		{
+			title:  "solid white landscape",
+			img:    solid(64, 32, color.White),
+			header: []byte{0x3F, 0x08, 0x02, 0x04, 0x80},
+			length: 19,
+		},
+		{
+			title:  "solid white portrait",
+			img:    solid(32, 64, color.White),
+			header: []byte{0x3F, 0x08, 0x02, 0x04, 0x00},
+			length: 19,
+		},
+		{
+			title:  "transparent",
+			img:    solid(32, 32, color.Transparent),
+			header: []byte{0x00, 0x08, 0x82, 0x05, 0x00, 0x00},
+			length: 25,
+		},
+		{
+			title: "pattern",
+			img:   pattern(7, 5, false),
+			header: []byte{
+				0x1C, 0x08, 0x0E, 0x25, 0x92, 0x60, 0x96, 0x84, 0x76, 0x97, 0x78, 0x76,
+				0x77, 0x78, 0x86, 0x96, 0x50, 0x6B, 0xFE, 0x99, 0x76,
+			},
+			length: 21,
+		},
+		{
+			title: "translucent pattern",
+			img:   pattern(7, 5, true),
+			header: []byte{
+				0xDC, 0x07, 0x8A, 0x1C, 0x8E, 0x0C, 0x60, 0xA7, 0x75, 0x97, 0x76, 0x68,
+				0x27, 0xC6, 0xF6, 0x9F, 0x69, 0x97, 0xA6, 0x62, 0x57, 0x57, 0x5A, 0x50,
+				0x0A,
+			},
+			length: 25,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			hash, err := ThumbHash(test.img)
+			require.Nil(t, err)
+
+			assert.Equal(t, test.length, len(hash))
+			assert.Equal(t, test.header, hash[:len(test.header)])
+
+		})
+	}
+
+}
+
+func TestPhoto(t *testing.T) {
+
+	file, err := os.Open(path.Join("..", "testdata", "plush.jpg"))
+	require.Nil(t, err)
+	defer func() {
+		if err := file.Close(); err != nil {
+			panic(err.Error())
+		}
+	}()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+
+	// Both hashes match the reference implementations given the same
+	// downsampled pixels
+	blurhash, err := BlurHash(img, 4, 3)
+	require.Nil(t, err)
+	assert.Equal(t, "LdJ@|DoM-it7?wflShj]-kkCRkWB", blurhash)
+
+	thumbhash, err := ThumbHash(img)
+	require.Nil(t, err)
+	assert.Equal(t, []byte{
+		0xE5, 0x07, 0x0A, 0x25, 0x04, 0xB7, 0xB8, 0x7F, 0x87, 0xA8, 0x78, 0xB6,
+		0x88, 0x84, 0x7B, 0xB7, 0xC7, 0x72, 0x8F, 0x1A, 0xF7,
+	}, thumbhash)
+
+}
+
+func TestImage(t *testing.T) {
+
+	// The top half of the image is red, and the bottom half is blue
+	split := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			if y < 2 {
+				split.SetRGBA(x, y, color.RGBA{255, 0, 0, 0xFF})
+			} else {
+				split.SetRGBA(x, y, color.RGBA{0, 0, 255, 0xFF})
+			}
+		}
+	}
+
+	tests := []struct {
+		title  string
+		img    image.Image
+		width  int
+		height int
+		style  Style
+		pixels []color.RGBA
+	}{
+		{
+			title:  "solid",
+			img:    split,
+			width:  2,
+			height: 1,
+			style:  Solid,
+			pixels: []color.RGBA{
+				{128, 0, 128, 0xFF},
+				{128, 0, 128, 0xFF},
+			},
+		},
+		{
+			title:  "gradient",
+			img:    split,
+			width:  1,
+			height: 4,
+			style:  Gradient,
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{191, 0, 64, 0xFF},
+				{64, 0, 191, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+		},
+		{
+			title:  "mosaic of a solid image",
+			img:    solid(8, 8, color.RGBA{40, 80, 120, 0xFF}),
+			width:  2,
+			height: 2,
+			style:  Mosaic,
+			pixels: []color.RGBA{
+				{40, 80, 120, 0xFF},
+				{40, 80, 120, 0xFF},
+				{40, 80, 120, 0xFF},
+				{40, 80, 120, 0xFF},
+			},
+		},
+		{
+			title:  "mosaic",
+			img:    split,
+			width:  1,
+			height: 2,
+			style:  Mosaic,
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			placeholder, err := Image(test.img, test.width, test.height, test.style)
+			require.Nil(t, err)
+			require.Equal(t, image.Rect(0, 0, test.width, test.height), placeholder.Bounds())
+
+			var pixels []color.RGBA
+			for y := 0; y < test.height; y++ {
+				for x := 0; x < test.width; x++ {
+					pixels = append(pixels, placeholder.RGBAAt(x, y))
+				}
+			}
+			assert.Equal(t, test.pixels, pixels)
+
+		})
+	}
+
+	_, err := Image(split, 0, 4, Solid)
+	assert.Equal(t, ErrInvalidSize, err)
+
+	_, err = Image(image.NewRGBA(image.Rect(0, 0, 0, 0)), 4, 4, Solid)
+	assert.NotNil(t, err)
+
+	_, err = Image(split, 4, 4, Style(-1))
+	assert.NotNil(t, err)
+
+}
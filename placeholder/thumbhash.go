@@ -0,0 +1,181 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// thumbHashLimit is the largest dimension supported by the ThumbHash encoding.
+const thumbHashLimit = 100
+
+// ThumbHash takes in an image, and returns its binary ThumbHash. Images larger
+// than 100x100 are downsampled first, as required by the encoding.
+func ThumbHash(img image.Image) ([]byte, error) {
+
+	width, height, pixels := downsample(img, thumbHashLimit)
+
+	if width == 0 || height == 0 {
+		return nil, errors.New("image has no pixels")
+	}
+
+	// Determine the average color, weighted by alpha
+	var avgR, avgG, avgB, avgA float64
+	for _, pixel := range pixels {
+		alpha := float64(pixel.A) / 255
+		avgR += alpha / 255 * float64(pixel.R)
+		avgG += alpha / 255 * float64(pixel.G)
+		avgB += alpha / 255 * float64(pixel.B)
+		avgA += alpha
+	}
+	if avgA > 0 {
+		avgR /= avgA
+		avgG /= avgA
+		avgB /= avgA
+	}
+
+	hasAlpha := avgA < float64(width*height)
+
+	// Use fewer luminance bits if there is alpha
+	limit := 7.0
+	if hasAlpha {
+		limit = 5
+	}
+
+	longest := float64(width)
+	if height > width {
+		longest = float64(height)
+	}
+
+	lx := int(math.Max(1, round(limit*float64(width)/longest)))
+	ly := int(math.Max(1, round(limit*float64(height)/longest)))
+
+	// Convert from RGBA into LPQA, composited atop the average color
+	l := make([]float64, len(pixels))
+	p := make([]float64, len(pixels))
+	q := make([]float64, len(pixels))
+	a := make([]float64, len(pixels))
+
+	for index, pixel := range pixels {
+		alpha := float64(pixel.A) / 255
+		r := avgR*(1-alpha) + alpha/255*float64(pixel.R)
+		g := avgG*(1-alpha) + alpha/255*float64(pixel.G)
+		b := avgB*(1-alpha) + alpha/255*float64(pixel.B)
+
+		l[index] = (r + g + b) / 3
+		p[index] = (r+g)/2 - b
+		q[index] = r - g
+		a[index] = alpha
+	}
+
+	lDC, lAC, lScale := encodeChannel(l, width, height, max(3, lx), max(3, ly))
+	pDC, pAC, pScale := encodeChannel(p, width, height, 3, 3)
+	qDC, qAC, qScale := encodeChannel(q, width, height, 3, 3)
+
+	var aDC, aScale float64
+	var aAC []float64
+	if hasAlpha {
+		aDC, aAC, aScale = encodeChannel(a, width, height, 5, 5)
+	}
+
+	// Write the constants
+	isLandscape := width > height
+
+	header24 := int(round(63*lDC)) |
+		int(round(31.5+31.5*pDC))<<6 |
+		int(round(31.5+31.5*qDC))<<12 |
+		int(round(31*lScale))<<18
+	if hasAlpha {
+		header24 |= 1 << 23
+	}
+
+	header16 := ly
+	if !isLandscape {
+		header16 = lx
+	}
+	header16 |= int(round(63*pScale))<<3 | int(round(63*qScale))<<9
+	if isLandscape {
+		header16 |= 1 << 15
+	}
+
+	hash := []byte{
+		byte(header24),
+		byte(header24 >> 8),
+		byte(header24 >> 16),
+		byte(header16),
+		byte(header16 >> 8),
+	}
+
+	channels := [][]float64{lAC, pAC, qAC}
+	if hasAlpha {
+		hash = append(hash, byte(int(round(15*aDC))|int(round(15*aScale))<<4))
+		channels = append(channels, aAC)
+	}
+
+	// Write the varying factors, packed two per byte
+	start := len(hash)
+	index := 0
+	for _, ac := range channels {
+		for _, factor := range ac {
+			if start+index/2 >= len(hash) {
+				hash = append(hash, 0)
+			}
+			hash[start+index/2] |= byte(int(round(15*factor)) << uint((index&1)*4))
+			index++
+		}
+	}
+
+	return hash, nil
+}
+
+// encodeChannel performs a DCT over the given channel, and returns the DC
+// term, the AC terms normalized into [0, 1], and the AC normalization scale.
+func encodeChannel(channel []float64, width int, height int, nx int, ny int) (float64, []float64, float64) {
+
+	var dc, scale float64
+	var ac []float64
+
+	fx := make([]float64, width)
+
+	for cy := 0; cy < ny; cy++ {
+		for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+
+			for x := 0; x < width; x++ {
+				fx[x] = math.Cos(math.Pi / float64(width) * float64(cx) * (float64(x) + 0.5))
+			}
+
+			var f float64
+			for y := 0; y < height; y++ {
+				fy := math.Cos(math.Pi / float64(height) * float64(cy) * (float64(y) + 0.5))
+				for x := 0; x < width; x++ {
+					f += channel[x+y*width] * fx[x] * fy
+				}
+			}
+			f /= float64(width * height)
+
+			if cx > 0 || cy > 0 {
+				ac = append(ac, f)
+				scale = math.Max(scale, math.Abs(f))
+			} else {
+				dc = f
+			}
+		}
+	}
+
+	if scale > 0 {
+		for index := range ac {
+			ac[index] = 0.5 + 0.5/scale*ac[index]
+		}
+	}
+
+	return dc, ac, scale
+}
+
+// round rounds half values upwards, matching the reference implementation.
+func round(value float64) float64 {
+	return math.Floor(value + 0.5)
+}
@@ -0,0 +1,465 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"sort"
+)
+
+const (
+	// MaxLevels is the largest number of levels that MMCQ may be performed
+	// to, yielding a palette of 65536 colors.
+	MaxLevels = 16
+
+	// MaxColors is the largest number of colors that may be requested.
+	MaxColors = 1 << MaxLevels
+)
+
+var (
+	// ErrInvalidLevels is returned when the number of levels is not within
+	// [0, MaxLevels].
+	ErrInvalidLevels = errors.New("levels must be between 0 and 16")
+
+	// ErrInvalidColors is returned when the number of colors is not within
+	// [1, MaxColors].
+	ErrInvalidColors = errors.New("colors must be between 1 and 65536")
+)
+
+// Quantizer performs MMCQ while retaining its internal buffers between calls,
+// so that repeatedly quantizing many images (such as thumbnails in a busy
+// server) avoids reallocating them each time. The zero value is ready to use.
+// A Quantizer is not safe for concurrent use, but a pool of them may be.
+type Quantizer struct {
+	pixels     []color.RGBA
+	weights    []float64
+	counted    bool
+	partitions []box
+	next       []box
+
+	truncate bool
+	short    bool
+	filter   func(x int, y int, c color.RGBA) bool
+	weight   func(x int, y int, c color.RGBA) float64
+	order    bool
+
+	exemplars bool
+
+	reference []color.RGBA
+
+	maxWidth  int
+	maxHeight int
+	maxPixels int
+
+	thumbnail bool
+
+	trace  *Trace
+	labels context.Context
+
+	uniform    uint8
+	exhaustive bool
+
+	format Format
+}
+
+// Option configures the behavior of a Quantizer.
+type Option func(*Quantizer)
+
+// NewQuantizer returns a Quantizer configured with the given options.
+func NewQuantizer(options ...Option) *Quantizer {
+	quantizer := &Quantizer{}
+
+	for _, option := range options {
+		option(quantizer)
+	}
+
+	return quantizer
+}
+
+// WithTruncatedAverage averages palette colors using truncating integer
+// division, rather than rounding to the nearest value. This reproduces the
+// slightly darker palettes of earlier releases, for compatibility with
+// existing golden tests. Paletted images are also partitioned pixel by pixel,
+// as earlier releases did, rather than from a histogram of their palette.
+func WithTruncatedAverage() Option {
+	return func(q *Quantizer) {
+		q.truncate = true
+	}
+}
+
+// WithAllowShortPalette returns only as many colors as there are distinct
+// colors in the pixels, rather than padding the palette to 2^levels colors by
+// repeating them. Identical colors are also never returned more than once.
+// This suits icons and logos with only a handful of colors.
+func WithAllowShortPalette() Option {
+	return func(q *Quantizer) {
+		q.short = true
+	}
+}
+
+// WithSimilarityOrder orders the palette with SimilarityOrder, so that
+// adjacent colors are perceptually close.
+func WithSimilarityOrder() Option {
+	return func(q *Quantizer) {
+		q.order = true
+	}
+}
+
+// WithReferencePalette aligns the palette with the given reference palette
+// using Align, so that requantizing a new version of an image, or the next
+// frame of a video, keeps colors at the same indices where possible. This is
+// applied after WithSimilarityOrder.
+func WithReferencePalette(reference []color.RGBA) Option {
+	return func(q *Quantizer) {
+		q.reference = reference
+	}
+}
+
+// WithExemplars includes an exemplar source pixel in every swatch returned by
+// Analyze, at the cost of a second pass over the image.
+func WithExemplars() Option {
+	return func(q *Quantizer) {
+		q.exemplars = true
+	}
+}
+
+// WithUniformThreshold treats an image as uniform when the spread of each of
+// its color components is at most the given threshold, rather than only when
+// every pixel is identical. Uniform images skip partitioning altogether, and
+// are reduced to their average color, repeated to fill the palette unless the
+// Quantizer was created with WithAllowShortPalette.
+func WithUniformThreshold(threshold uint8) Option {
+	return func(q *Quantizer) {
+		q.uniform = threshold
+	}
+}
+
+// WithExhaustive returns the exact distinct colors of an image, in order of
+// decreasing population, instead of performing MMCQ whenever there are no more
+// of them than the palette holds. Palettes of logos & pixel art are then
+// lossless. Applies to Image, Colors, Pixels, RGBBuffer, and Raw.
+func WithExhaustive() Option {
+	return func(q *Quantizer) {
+		q.exhaustive = true
+	}
+}
+
+// WithPixelFilter only quantizes the pixels of an image for which the given
+// function returns true, such as only saturated pixels, or only the pixels
+// within a segmentation mask. The function is called with the coordinates and
+// RGB color of each pixel, without copying the image first. The filter does
+// not apply to pixels given directly to Pixels, since they have no
+// coordinates.
+func WithPixelFilter(filter func(x int, y int, c color.RGBA) bool) Option {
+	return func(q *Quantizer) {
+		q.filter = filter
+	}
+}
+
+// WithPixelWeight weights each pixel of an image by the value the given
+// function returns for it, such as from a vignette, a saliency map, or a
+// foreground probability map. Partitions are split at their weighted median,
+// and palette colors are weighted averages. Pixels with a weight of zero or
+// less are dropped entirely. As with WithPixelFilter, the weight does not
+// apply to pixels given directly to Pixels.
+func WithPixelWeight(weight func(x int, y int, c color.RGBA) float64) Option {
+	return func(q *Quantizer) {
+		q.weight = weight
+	}
+}
+
+// Pixels takes in a slice of RGB pixels, and performs the MMCQ process to the
+// specified number of levels. Returns a slice of RGB colors of length 2^levels.
+// The given pixels are reordered in place.
+//
+// Partitions holding a single distinct color are never split. Instead, their
+// splits are redistributed to the most populous partitions that can still be
+// split. If the pixels hold fewer than 2^levels distinct colors, the palette
+// is padded by repeating colors, so that every color returned is real, unless
+// the Quantizer was created with WithAllowShortPalette. Returns
+// ErrInvalidLevels if levels is not within [0, MaxLevels].
+func (q *Quantizer) Pixels(pixels []color.RGBA, levels int) ([]color.RGBA, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return nil, ErrInvalidLevels
+	}
+
+	return q.quantize(pixels, nil, levels, 1<<uint(levels)), nil
+}
+
+// quantize splits the given pixels, which are optionally weighted, into the
+// target number of partitions, and returns the palette of their averages.
+func (q *Quantizer) quantize(pixels []color.RGBA, weights []float64, levels int, target int) []color.RGBA {
+
+	averages, found := q.exact(pixels, weights, target)
+
+	if !found {
+		partitions := q.partition(pixels, weights, levels, target)
+		averages = make([]color.RGBA, len(partitions), target)
+
+		for index, partition := range partitions {
+			averages[index] = partition.average(q.truncate)
+		}
+
+		// Avoid retaining the caller's pixels once finished
+		q.release()
+	}
+
+	if q.format != 0 {
+		averages = Snap(averages, q.format)
+	}
+
+	if q.short {
+		// A color may straddle a median, and so average identically in two
+		// partitions
+		averages = distinct(averages)
+	} else {
+		// Pad the palette when there are too few distinct colors to fill it
+		for index := 0; len(averages) < target; index++ {
+			averages = append(averages, averages[index])
+		}
+	}
+
+	if q.order {
+		averages = SimilarityOrder(averages)
+	}
+
+	if q.reference != nil {
+		averages = Align(averages, q.reference)
+	}
+
+	return averages
+}
+
+// partition splits the given pixels, which are optionally weighted, level by
+// level to the specified number of levels, and then redistributes any further
+// splits needed to reach the target number of partitions. The partitions are
+// held in the internal buffers until they are released.
+//
+// Only two buffers of boxes are ever used, one for the current level and one
+// for the next, and each box is a view into the single pixel buffer. Memory
+// for the partitions is therefore bounded by the number of output colors,
+// however many levels are requested, and is reused between calls.
+func (q *Quantizer) partition(pixels []color.RGBA, weights []float64, levels int, target int) []box {
+
+	var partitions []box
+
+	q.phase(phaseSplit, func() {
+		partitions = append(q.partitions[:0], newBox(pixels, weights))
+		next := q.next[:0]
+
+		// Uniform images, such as solid icons, need no splits at all
+		if q.isUniform(pixels) {
+			q.partitions, q.next = partitions, next
+			return
+		}
+
+		for iteration := 0; iteration < levels; iteration++ {
+
+			for _, partition := range partitions {
+				if left, right, ok := split(partition); ok {
+					next = append(next, left, right)
+				} else {
+					next = append(next, partition)
+				}
+			}
+
+			partitions, next = next, partitions[:0]
+		}
+
+		partitions = redistribute(partitions, target)
+		q.partitions, q.next = partitions, next
+	})
+
+	return partitions
+}
+
+// exact returns the distinct colors of the given pixels, in order of
+// decreasing population, if exhaustive palettes were requested and there are
+// at most the target number of them. Ties are broken by hex value, so that
+// the order is stable.
+func (q *Quantizer) exact(pixels []color.RGBA, weights []float64, target int) ([]color.RGBA, bool) {
+
+	if !q.exhaustive || len(pixels) == 0 {
+		return nil, false
+	}
+
+	populations := map[color.RGBA]float64{}
+	for index, pixel := range pixels {
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[index]
+		}
+
+		populations[pixel] += weight
+		if len(populations) > target {
+			return nil, false
+		}
+	}
+
+	colors := make([]color.RGBA, 0, target)
+	for clr := range populations {
+		colors = append(colors, clr)
+	}
+
+	sort.Slice(colors, func(i int, j int) bool {
+		if populations[colors[i]] != populations[colors[j]] {
+			return populations[colors[i]] > populations[colors[j]]
+		}
+		return Hex(colors[i]) < Hex(colors[j])
+	})
+
+	return colors, true
+}
+
+// isUniform reports whether the spread of each color component of the given
+// pixels is within the uniform threshold. Partitions holding a single distinct
+// color can not be split anyway, so checking them once up front saves
+// spreading every pixel again at each level.
+func (q *Quantizer) isUniform(pixels []color.RGBA) bool {
+	r, g, b := Spread(pixels)
+	return r <= q.uniform && g <= q.uniform && b <= q.uniform
+}
+
+// release clears the internal partition buffers, so that no references to
+// the pixels are retained.
+func (q *Quantizer) release() {
+	for _, buf := range [][]box{q.partitions[:cap(q.partitions)], q.next[:cap(q.next)]} {
+		for index := range buf {
+			buf[index] = box{}
+		}
+	}
+
+	q.partitions, q.next = q.partitions[:0], q.next[:0]
+}
+
+// distinct removes repeated colors from the given palette in place, keeping the
+// first occurrence of each.
+func distinct(colors []color.RGBA) []color.RGBA {
+	seen := make(map[color.RGBA]bool, len(colors))
+	unique := colors[:0]
+
+	for _, clr := range colors {
+		if !seen[clr] {
+			seen[clr] = true
+			unique = append(unique, clr)
+		}
+	}
+
+	return unique
+}
+
+// Image is a helper that converts the given image into a slice of RGB pixels
+// before performing MMCQ.
+func (q *Quantizer) Image(img image.Image, levels int) ([]color.RGBA, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return nil, ErrInvalidLevels
+	}
+
+	return q.region(img, img.Bounds(), levels), nil
+}
+
+// Colors takes in an image, and performs MMCQ until the palette holds exactly
+// n colors. Rather than splitting every partition at each level, the most
+// populous partition is split each time, so n need not be a power of two.
+func (q *Quantizer) Colors(img image.Image, n int) ([]color.RGBA, error) {
+
+	if n < 1 || n > MaxColors {
+		return nil, ErrInvalidColors
+	}
+
+	q.extractHistogram(img, img.Bounds())
+	return q.quantize(q.pixels, q.weights, 0, n), nil
+}
+
+// region performs MMCQ on the pixels of the given image that lie within the
+// given rectangle. The levels must already be within [0, MaxLevels].
+func (q *Quantizer) region(img image.Image, rect image.Rectangle, levels int) []color.RGBA {
+	q.extractHistogram(img, rect)
+	return q.quantize(q.pixels, q.weights, levels, 1<<uint(levels))
+}
+
+// extract fills the pixel buffer with the pixels of the given image that lie
+// within the given rectangle, and which pass the pixel filter. If the pixels
+// are weighted, the weight buffer is filled with the weight of each pixel.
+func (q *Quantizer) extract(img image.Image, rect image.Rectangle) {
+	q.phase(phaseExtract, func() {
+		q.pixels = extract(q.pixels, img, rect)
+		q.retain(rect.Intersect(img.Bounds()))
+	})
+}
+
+// extractHistogram is like extract, except that paletted images are reduced
+// to their palette colors, weighted by how many pixels use each of them, in
+// which case the weights are pixel counts. This is only possible when neither
+// a pixel filter nor a pixel weight needs the coordinates of every pixel.
+// Truncated averages reproduce the palettes of earlier releases, so every
+// pixel is always extracted for them.
+func (q *Quantizer) extractHistogram(img image.Image, rect image.Rectangle) {
+	paletted, ok := img.(*image.Paletted)
+	if !ok || q.filter != nil || q.weight != nil || q.truncate {
+		q.extract(img, rect)
+		q.counted = false
+		return
+	}
+
+	q.phase(phaseExtract, func() {
+		q.pixels, q.weights = histogramPaletted(q.pixels, q.weights, paletted, rect)
+		q.counted = true
+	})
+}
+
+// retain keeps only the pixels in the pixel buffer, as extracted column by
+// column from the given rectangle, which pass the pixel filter, and fills the
+// weight buffer if the pixels are weighted.
+func (q *Quantizer) retain(rect image.Rectangle) {
+	q.weights = q.weights[:0]
+
+	if q.filter == nil && q.weight == nil {
+		return
+	}
+
+	// Pixels are extracted column by column, so their coordinates follow from
+	// their index alone
+	height := rect.Dy()
+	kept := q.pixels[:0]
+
+	for index, pixel := range q.pixels {
+		x, y := rect.Min.X+index/height, rect.Min.Y+index%height
+
+		if q.filter != nil && !q.filter(x, y, pixel) {
+			continue
+		}
+
+		if q.weight != nil {
+			// Negated, so that NaN weights are also dropped
+			weight := q.weight(x, y, pixel)
+			if !(weight > 0) {
+				continue
+			}
+			q.weights = append(q.weights, weight)
+		}
+
+		kept = append(kept, pixel)
+	}
+
+	q.pixels = kept
+}
+
+// clampLevels limits the given levels to within [0, MaxLevels].
+func clampLevels(levels int) int {
+	switch {
+	case levels < 0:
+		return 0
+	case levels > MaxLevels:
+		return MaxLevels
+	default:
+		return levels
+	}
+}
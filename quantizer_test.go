@@ -0,0 +1,492 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantizerReuse(t *testing.T) {
+
+	var images []image.Image
+
+	for _, name := range []string{"plush.jpg", "plush.png", "plush.gif"} {
+		file, err := os.Open(path.Join("testdata", name))
+		require.Nil(t, err)
+
+		img, _, err := image.Decode(file)
+		require.Nil(t, err)
+		require.Nil(t, file.Close())
+
+		images = append(images, img)
+	}
+
+	// Include a tiny image, so that buffers must also shrink correctly
+	images = append(images, testImage(2, 1, func(x int, _ int) color.RGBA {
+		return color.RGBA{uint8(x * 255), 0, 0, 0xFF}
+	}))
+
+	var quantizer Quantizer
+
+	for round := 0; round < 2; round++ {
+		for index, img := range images {
+			name := fmt.Sprintf("Case #%d - round %d image %d", round*len(images)+index, round, index)
+
+			t.Run(name, func(t *testing.T) {
+
+				for _, levels := range []int{0, 3} {
+					palette, err := quantizer.Image(img, levels)
+					require.Nil(t, err)
+					assert.Equal(t, Image(img, levels), palette)
+				}
+
+			})
+		}
+	}
+
+}
+
+func TestQuantizerBuffers(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), 0, 0xFF}
+	})
+
+	var quantizer Quantizer
+
+	quantizer.Image(img, 3)
+	pixels := &quantizer.pixels[:1][0]
+
+	quantizer.Image(img, 3)
+	assert.True(t, pixels == &quantizer.pixels[:1][0], "pixel buffer was reallocated")
+
+	// No references to pixels should be retained
+	for _, buf := range [][]box{quantizer.partitions[:cap(quantizer.partitions)], quantizer.next[:cap(quantizer.next)]} {
+		for _, partition := range buf {
+			assert.Nil(t, partition.pixels)
+		}
+	}
+
+}
+
+func TestQuantizerShortPalette(t *testing.T) {
+
+	tests := []struct {
+		title   string
+		pixels  []color.RGBA
+		levels  int
+		palette []color.RGBA
+	}{
+		{
+			title:  "0 pixels",
+			pixels: []color.RGBA{},
+			levels: 2,
+			palette: []color.RGBA{
+				{0, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "1 color",
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+			levels: 2,
+			palette: []color.RGBA{
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "3 colors",
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{0, 255, 0, 0xFF},
+				{0, 0, 255, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+			levels: 3,
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{0, 255, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "enough colors",
+			pixels: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{0, 255, 0, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+			levels: 1,
+			palette: []color.RGBA{
+				{0, 255, 0, 0xFF},
+				{128, 0, 128, 0xFF},
+			},
+		},
+	}
+
+	quantizer := NewQuantizer(WithAllowShortPalette())
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palette, err := quantizer.Pixels(test.pixels, test.levels)
+			require.Nil(t, err)
+			assert.Equal(t, test.palette, palette)
+
+		})
+	}
+
+}
+
+func TestQuantizerInvalid(t *testing.T) {
+
+	img := testImage(2, 2, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 255), uint8(y * 255), 0, 0xFF}
+	})
+
+	var quantizer Quantizer
+
+	for index, levels := range []int{-1, MaxLevels + 1, 30} {
+		name := fmt.Sprintf("Case #%d - %d levels", index, levels)
+
+		t.Run(name, func(t *testing.T) {
+
+			_, err := quantizer.Image(img, levels)
+			assert.Equal(t, ErrInvalidLevels, err)
+
+			_, err = quantizer.Pixels([]color.RGBA{}, levels)
+			assert.Equal(t, ErrInvalidLevels, err)
+
+		})
+	}
+
+	for index, n := range []int{-1, 0, MaxColors + 1} {
+		name := fmt.Sprintf("Case #%d - %d colors", index, n)
+
+		t.Run(name, func(t *testing.T) {
+
+			_, err := quantizer.Colors(img, n)
+			assert.Equal(t, ErrInvalidColors, err)
+
+		})
+	}
+
+	colors, err := quantizer.Colors(img, 3)
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(colors))
+
+}
+
+func TestQuantizerPixelFilter(t *testing.T) {
+
+	// The left half of the image is red, and the right half is blue
+	img := testImage(4, 3, func(x int, y int) color.RGBA {
+		if x < 2 {
+			return color.RGBA{255, 0, 0, 0xFF}
+		}
+		return color.RGBA{0, 0, 255, 0xFF}
+	})
+
+	tests := []struct {
+		title   string
+		filter  func(x int, y int, c color.RGBA) bool
+		palette []color.RGBA
+	}{
+		{
+			title: "only the left half",
+			filter: func(x int, _ int, _ color.RGBA) bool {
+				return x < 2
+			},
+			palette: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "only blue pixels",
+			filter: func(_ int, _ int, c color.RGBA) bool {
+				return c.B > 0
+			},
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+		},
+		{
+			title: "coordinates",
+			filter: func(x int, y int, _ color.RGBA) bool {
+				return x == 3 && y == 2
+			},
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{0, 0, 255, 0xFF},
+			},
+		},
+		{
+			title: "no pixels",
+			filter: func(_ int, _ int, _ color.RGBA) bool {
+				return false
+			},
+			palette: []color.RGBA{
+				{0, 0, 0, 0xFF},
+				{0, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "every pixel",
+			filter: func(_ int, _ int, _ color.RGBA) bool {
+				return true
+			},
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palette, err := NewQuantizer(WithPixelFilter(test.filter)).Image(img, 1)
+			require.Nil(t, err)
+
+			assert.Equal(t, test.palette, palette)
+
+		})
+	}
+
+}
+
+func TestQuantizerPixelWeight(t *testing.T) {
+
+	// The left half of the image is red, and the right half is blue
+	img := testImage(4, 3, func(x int, y int) color.RGBA {
+		if x < 2 {
+			return color.RGBA{255, 0, 0, 0xFF}
+		}
+		return color.RGBA{0, 0, 255, 0xFF}
+	})
+
+	tests := []struct {
+		title   string
+		weight  func(x int, y int, c color.RGBA) float64
+		levels  int
+		palette []color.RGBA
+	}{
+		{
+			title: "equal weights",
+			weight: func(_ int, _ int, _ color.RGBA) float64 {
+				return 1
+			},
+			levels: 0,
+			palette: []color.RGBA{
+				{128, 0, 128, 0xFF},
+			},
+		},
+		{
+			title: "heavier red",
+			weight: func(_ int, _ int, c color.RGBA) float64 {
+				if c.R > 0 {
+					return 3
+				}
+				return 1
+			},
+			levels: 0,
+			palette: []color.RGBA{
+				{191, 0, 64, 0xFF},
+			},
+		},
+		{
+			title: "dropped blue",
+			weight: func(x int, _ int, _ color.RGBA) float64 {
+				if x < 2 {
+					return 0.5
+				}
+				return math.NaN()
+			},
+			levels: 1,
+			palette: []color.RGBA{
+				{255, 0, 0, 0xFF},
+				{255, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "weighted median within heavier blue",
+			weight: func(x int, _ int, _ color.RGBA) float64 {
+				return float64(x + 1)
+			},
+			levels: 1,
+			palette: []color.RGBA{
+				{0, 0, 255, 0xFF},
+				{135, 0, 120, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			palette, err := NewQuantizer(WithPixelWeight(test.weight)).Image(img, test.levels)
+			require.Nil(t, err)
+
+			assert.Equal(t, test.palette, palette)
+
+		})
+	}
+
+}
+
+func TestQuantizerEqualWeights(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), uint8(x * y), 0xFF}
+	})
+
+	quantizer := NewQuantizer(WithPixelWeight(func(_ int, _ int, _ color.RGBA) float64 {
+		return 2
+	}))
+
+	for _, levels := range []int{0, 1, 3, 5} {
+		palette, err := quantizer.Image(img, levels)
+		require.Nil(t, err)
+
+		assert.Equal(t, Image(img, levels), palette)
+	}
+
+}
+
+func TestUniformThreshold(t *testing.T) {
+
+	solid := color.RGBA{40, 80, 120, 0xFF}
+	noisy := testImage(8, 8, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(40 + (x+y)%3), 80, uint8(120 - x%2), 0xFF}
+	})
+
+	tests := []struct {
+		title     string
+		options   []Option
+		img       image.Image
+		levels    int
+		colors    []color.RGBA
+		partition bool
+	}{
+		{
+			title:  "uniform image",
+			img:    testImage(8, 8, func(int, int) color.RGBA { return solid }),
+			levels: 2,
+			colors: []color.RGBA{solid, solid, solid, solid},
+		},
+		{
+			title:   "uniform image with a short palette",
+			options: []Option{WithAllowShortPalette()},
+			img:     testImage(8, 8, func(int, int) color.RGBA { return solid }),
+			levels:  2,
+			colors:  []color.RGBA{solid},
+		},
+		{
+			title:     "nearly uniform image",
+			img:       noisy,
+			levels:    1,
+			partition: true,
+		},
+		{
+			title:   "nearly uniform image within the threshold",
+			options: []Option{WithUniformThreshold(2)},
+			img:     noisy,
+			levels:  1,
+			colors:  []color.RGBA{Average(extract(nil, noisy, noisy.Bounds())), Average(extract(nil, noisy, noisy.Bounds()))},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			colors, err := NewQuantizer(test.options...).Image(test.img, test.levels)
+			require.Nil(t, err)
+
+			if test.partition {
+				assert.NotEqual(t, colors[0], colors[1])
+				return
+			}
+			assert.Equal(t, test.colors, colors)
+
+		})
+	}
+}
+
+func TestExhaustive(t *testing.T) {
+
+	// Three colors in unequal amounts, which MMCQ would blend at one level
+	img := testImage(6, 2, func(x int, _ int) color.RGBA {
+		switch {
+		case x < 3:
+			return red
+		case x < 5:
+			return blue
+		default:
+			return color.RGBA{0, 0, 200, 0xFF}
+		}
+	})
+
+	tests := []struct {
+		title   string
+		options []Option
+		levels  int
+		colors  []color.RGBA
+	}{
+		{
+			title:  "exact colors",
+			levels: 2,
+			colors: []color.RGBA{red, blue, {0, 0, 200, 0xFF}, red},
+		},
+		{
+			title:   "exact colors with a short palette",
+			options: []Option{WithAllowShortPalette()},
+			levels:  2,
+			colors:  []color.RGBA{red, blue, {0, 0, 200, 0xFF}},
+		},
+		{
+			title:  "too many colors",
+			levels: 1,
+			colors: Image(img, 1),
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			colors, err := NewQuantizer(append(test.options, WithExhaustive())...).Image(img, test.levels)
+			require.Nil(t, err)
+			assert.Equal(t, test.colors, colors)
+
+		})
+	}
+
+	// Palettes of any size may be exhaustive
+	colors, err := NewQuantizer(WithExhaustive()).Colors(img, 3)
+	require.Nil(t, err)
+	assert.Equal(t, []color.RGBA{red, blue, {0, 0, 200, 0xFF}}, colors)
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package quantizetest provides helpers for testing code that produces
+// palettes. Palettes are compared by perceptual distance within a tolerance,
+// rather than byte for byte, so that tests remain stable across small changes
+// in rounding, and across architectures.
+package quantizetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+
+	"github.com/joshdk/quantize"
+)
+
+// DefaultTolerance is the largest DeltaE between two colors that are
+// considered equal by default, which is roughly the smallest difference that
+// is noticeable to the human eye.
+const DefaultTolerance = 2.3
+
+// UpdateEnv is the environment variable that, when set to any non-empty value,
+// causes AssertGolden to rewrite golden files rather than compare against them.
+const UpdateEnv = "QUANTIZE_UPDATE_GOLDEN"
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Compare takes in an expected and an actual palette, and returns an error
+// describing the first pair of colors at the same index whose DeltaE exceeds
+// the given tolerance, or nil if the palettes match.
+func Compare(expected []color.RGBA, actual []color.RGBA, tolerance float64) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d colors, but got %d", len(expected), len(actual))
+	}
+
+	for index := range expected {
+		if distance := quantize.DeltaE(expected[index], actual[index]); distance > tolerance {
+			return fmt.Errorf("color %d: expected %s, but got %s (delta-E %.2f exceeds %.2f)",
+				index, quantize.Hex(expected[index]), quantize.Hex(actual[index]), distance, tolerance)
+		}
+	}
+
+	return nil
+}
+
+// CompareUnordered is like Compare, but ignores the order of the colors. The
+// actual palette is first aligned to the expected palette with quantize.Align.
+func CompareUnordered(expected []color.RGBA, actual []color.RGBA, tolerance float64) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d colors, but got %d", len(expected), len(actual))
+	}
+
+	return Compare(expected, quantize.Align(actual, expected), tolerance)
+}
+
+// AssertPalette reports a test error if the given palettes do not match at
+// every index within the given tolerance. Returns true if they match.
+func AssertPalette(t TestingT, expected []color.RGBA, actual []color.RGBA, tolerance float64) bool {
+	helper(t)
+	return report(t, Compare(expected, actual, tolerance))
+}
+
+// AssertPaletteUnordered reports a test error if the given palettes do not
+// match within the given tolerance, in any order. Returns true if they match.
+func AssertPaletteUnordered(t TestingT, expected []color.RGBA, actual []color.RGBA, tolerance float64) bool {
+	helper(t)
+	return report(t, CompareUnordered(expected, actual, tolerance))
+}
+
+// AssertGolden reports a test error if the given palette does not match the
+// palette stored as JSON in the golden file at the given path, within the
+// given tolerance. If the UpdateEnv environment variable is set, the golden
+// file is rewritten with the given palette instead. Returns true if they
+// match.
+func AssertGolden(t TestingT, path string, actual []color.RGBA, tolerance float64) bool {
+	helper(t)
+
+	if os.Getenv(UpdateEnv) != "" {
+		data, err := json.MarshalIndent(quantize.Palette(actual), "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+		}
+		return report(t, err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return report(t, err)
+	}
+
+	var expected quantize.Palette
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return report(t, fmt.Errorf("%s: %s", path, err.Error()))
+	}
+
+	if err := Compare(expected, actual, tolerance); err != nil {
+		return report(t, fmt.Errorf("%s: %s", path, err.Error()))
+	}
+
+	return true
+}
+
+// helper marks the caller as a test helper, if supported.
+func helper(t TestingT) {
+	if h, ok := t.(interface {
+		Helper()
+	}); ok {
+		h.Helper()
+	}
+}
+
+// report reports the given error, if any, and returns true if there was none.
+func report(t TestingT, err error) bool {
+	if err != nil {
+		t.Errorf("%s", err.Error())
+		return false
+	}
+	return true
+}
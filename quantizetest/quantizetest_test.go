@@ -0,0 +1,124 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantizetest
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures the errors reported by an assertion.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var (
+	navy  = color.RGBA{0x13, 0x25, 0x5c, 0xFF}
+	white = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+func TestCompare(t *testing.T) {
+
+	tests := []struct {
+		title     string
+		expected  []color.RGBA
+		actual    []color.RGBA
+		ordered   bool
+		unordered bool
+	}{
+		{
+			title:     "identical",
+			expected:  []color.RGBA{navy, white},
+			actual:    []color.RGBA{navy, white},
+			ordered:   true,
+			unordered: true,
+		},
+		{
+			title:     "within tolerance",
+			expected:  []color.RGBA{navy, white},
+			actual:    []color.RGBA{{0x14, 0x25, 0x5d, 0xFF}, {0xFE, 0xFE, 0xFF, 0xFF}},
+			ordered:   true,
+			unordered: true,
+		},
+		{
+			title:     "reordered",
+			expected:  []color.RGBA{navy, white},
+			actual:    []color.RGBA{white, navy},
+			unordered: true,
+		},
+		{
+			title:    "different color",
+			expected: []color.RGBA{navy, white},
+			actual:   []color.RGBA{navy, {0xC0, 0xC0, 0xC0, 0xFF}},
+		},
+		{
+			title:    "different length",
+			expected: []color.RGBA{navy, white},
+			actual:   []color.RGBA{navy},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.ordered, Compare(test.expected, test.actual, DefaultTolerance) == nil)
+			assert.Equal(t, test.unordered, CompareUnordered(test.expected, test.actual, DefaultTolerance) == nil)
+
+			var r recorder
+			assert.Equal(t, test.ordered, AssertPalette(&r, test.expected, test.actual, DefaultTolerance))
+			assert.Equal(t, test.ordered, len(r.errors) == 0)
+
+			r = recorder{}
+			assert.Equal(t, test.unordered, AssertPaletteUnordered(&r, test.expected, test.actual, DefaultTolerance))
+			assert.Equal(t, test.unordered, len(r.errors) == 0)
+
+		})
+	}
+
+}
+
+func TestAssertGolden(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "quantizetest")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "palette.json")
+	palette := []color.RGBA{navy, white}
+
+	// A missing golden file is an error
+	var r recorder
+	assert.False(t, AssertGolden(&r, path, palette, DefaultTolerance))
+	assert.Equal(t, 1, len(r.errors))
+
+	// Updating writes the golden file
+	require.Nil(t, os.Setenv(UpdateEnv, "1"))
+	r = recorder{}
+	assert.True(t, AssertGolden(&r, path, palette, DefaultTolerance))
+	require.Nil(t, os.Unsetenv(UpdateEnv))
+
+	data, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, "[\n  \"#13255C\",\n  \"#FFFFFF\"\n]\n", string(data))
+
+	r = recorder{}
+	assert.True(t, AssertGolden(&r, path, palette, DefaultTolerance))
+	assert.False(t, AssertGolden(&r, path, []color.RGBA{white, navy}, DefaultTolerance))
+	assert.Equal(t, 1, len(r.errors))
+
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import "container/heap"
+
+// node is a partition within the tree of splits made by redistribute. Leaves
+// are the current partitions, and every other node has been split in two.
+type node struct {
+	partition box
+
+	// parent is nil for the partitions given to redistribute, which are
+	// ordered by their index instead.
+	parent *node
+	index  int
+	right  bool
+	depth  int
+
+	// next links the nodes in their palette order, where split nodes are
+	// skipped over.
+	next  *node
+	split bool
+}
+
+// before reports whether the first leaf comes before the second in palette
+// order, which is the in-order position of each leaf within the tree.
+func before(first *node, second *node) bool {
+	for first.depth > second.depth {
+		first = first.parent
+	}
+	for second.depth > first.depth {
+		second = second.parent
+	}
+
+	// The leaves are distinct, and neither is an ancestor of the other
+	for first.parent != second.parent {
+		first, second = first.parent, second.parent
+	}
+
+	if first.parent == nil {
+		return first.index < second.index
+	}
+	return !first.right
+}
+
+// queue is a priority queue of leaves, ordered by decreasing population, with
+// ties broken in favor of the leaf that comes first in palette order.
+type queue []*node
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i int, j int) bool {
+	if q[i].partition.population != q[j].partition.population {
+		return q[i].partition.population > q[j].partition.population
+	}
+	return before(q[i], q[j])
+}
+
+func (q queue) Swap(i int, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(*node)) }
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// redistribute repeatedly splits the most populous partition that can still
+// be split, until there are the target number of partitions or no partition
+// can be split any further. Split partitions are replaced in place by their
+// halves, preserving the order of all other partitions. The partitions are held
+// in a priority queue bounded by the target, so that each split takes
+// logarithmic rather than linear time.
+func redistribute(partitions []box, target int) []box {
+
+	if len(partitions) >= target || len(partitions) == 0 {
+		return partitions
+	}
+
+	// Every split adds two nodes, so the nodes never outgrow their capacity,
+	// and pointers to them remain valid
+	nodes := make([]node, len(partitions), 2*target)
+	pending := make(queue, len(partitions), target)
+
+	for index := range partitions {
+		nodes[index] = node{partition: partitions[index], index: index}
+		if index > 0 {
+			nodes[index-1].next = &nodes[index]
+		}
+		pending[index] = &nodes[index]
+	}
+	heap.Init(&pending)
+
+	// Exhausted partitions leave the queue, but remain in the palette
+	for count := len(partitions); count < target && len(pending) > 0; {
+		candidate := heap.Pop(&pending).(*node)
+
+		left, right, ok := split(candidate.partition)
+		if !ok {
+			continue
+		}
+
+		nodes = append(nodes,
+			node{partition: left, parent: candidate, depth: candidate.depth + 1},
+			node{partition: right, parent: candidate, right: true, depth: candidate.depth + 1},
+		)
+		first, second := &nodes[len(nodes)-2], &nodes[len(nodes)-1]
+
+		// The candidate stays in the tree as an ancestor, and is followed by
+		// its halves in palette order
+		first.next, second.next = second, candidate.next
+		candidate.next, candidate.split = first, true
+
+		heap.Push(&pending, first)
+		heap.Push(&pending, second)
+		count++
+	}
+
+	result := partitions[:0]
+	for leaf := &nodes[0]; leaf != nil; leaf = leaf.next {
+		if !leaf.split {
+			result = append(result, leaf.partition)
+		}
+	}
+
+	return result
+}
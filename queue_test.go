@@ -0,0 +1,150 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// redistributeLinear is the straightforward implementation of redistribute,
+// which scans every partition for the most populous one before each split.
+func redistributeLinear(partitions []box, target int) []box {
+
+	var exhausted []bool
+	if len(partitions) < target {
+		exhausted = make([]bool, len(partitions), target)
+	}
+
+	for len(partitions) < target {
+
+		candidate := -1
+		for index, partition := range partitions {
+			if !exhausted[index] && (candidate < 0 || partition.population > partitions[candidate].population) {
+				candidate = index
+			}
+		}
+
+		if candidate < 0 {
+			break
+		}
+
+		left, right, ok := split(partitions[candidate])
+		if !ok {
+			exhausted[candidate] = true
+			continue
+		}
+
+		partitions = append(partitions, box{})
+		copy(partitions[candidate+2:], partitions[candidate+1:])
+		partitions[candidate], partitions[candidate+1] = left, right
+
+		exhausted = append(exhausted, false)
+		copy(exhausted[candidate+2:], exhausted[candidate+1:])
+		exhausted[candidate+1] = false
+	}
+
+	return partitions
+}
+
+func TestRedistribute(t *testing.T) {
+
+	random := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		title    string
+		pixels   int
+		values   int
+		weighted bool
+		initial  int
+		target   int
+	}{
+		{
+			title:  "single partition",
+			pixels: 500,
+			values: 256,
+			target: 37,
+		},
+		{
+			title:  "few distinct colors",
+			pixels: 400,
+			values: 3,
+			target: 64,
+		},
+		{
+			title:   "several partitions with ties",
+			pixels:  256,
+			values:  4,
+			initial: 4,
+			target:  23,
+		},
+		{
+			title:    "weighted",
+			pixels:   300,
+			values:   16,
+			weighted: true,
+			initial:  2,
+			target:   50,
+		},
+		{
+			title:  "target already reached",
+			pixels: 10,
+			values: 256,
+			target: 1,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			pixels := make([]color.RGBA, test.pixels)
+			var weights []float64
+			for index := range pixels {
+				pixels[index] = color.RGBA{
+					uint8(random.Intn(test.values)),
+					uint8(random.Intn(test.values)),
+					uint8(random.Intn(test.values)),
+					0xFF,
+				}
+				if test.weighted {
+					weights = append(weights, float64(random.Intn(3)+1))
+				}
+			}
+
+			// Both implementations reorder the pixels in place, so each is
+			// given its own copy of the same partitions
+			partitions := func() []box {
+				copied := append([]color.RGBA{}, pixels...)
+				var copiedWeights []float64
+				if weights != nil {
+					copiedWeights = append([]float64{}, weights...)
+				}
+
+				var quantizer Quantizer
+				levels := 0
+				for 1<<uint(levels) < test.initial {
+					levels++
+				}
+				return append([]box{}, quantizer.partition(copied, copiedWeights, levels, 0)...)
+			}
+
+			expected := redistributeLinear(partitions(), test.target)
+			actual := redistribute(partitions(), test.target)
+
+			assert.Equal(t, len(expected), len(actual))
+			for index := range expected {
+				assert.Equal(t, expected[index].pixels, actual[index].pixels)
+				assert.Equal(t, expected[index].population, actual[index].population)
+			}
+
+		})
+	}
+}
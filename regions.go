@@ -0,0 +1,91 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// RegionStats describes the connected regions of a single palette color
+// within an image remapped to its palette.
+type RegionStats struct {
+	// Count is the number of connected regions.
+	Count int `json:"count" yaml:"count"`
+
+	// Pixels is the total number of pixels across all regions.
+	Pixels int `json:"pixels" yaml:"pixels"`
+
+	// Largest is the number of pixels in the largest region.
+	Largest int `json:"largest" yaml:"largest"`
+
+	// Mean is the average number of pixels per region.
+	Mean float64 `json:"mean" yaml:"mean"`
+}
+
+// Regions takes in an image and a palette, remaps every pixel to its nearest
+// palette color, and returns the statistics of the connected regions of every
+// palette color, in the same order. Pixels are connected to their horizontal
+// and vertical neighbors of the same color. A color made up of one large
+// region, such as a sky, has a Largest close to its Pixels, while a color
+// scattered in specks has a high Count and a small Mean.
+func Regions(img image.Image, colors []color.RGBA) []RegionStats {
+	stats := make([]RegionStats, len(colors))
+
+	if len(colors) == 0 {
+		return stats
+	}
+
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	indices := remapIndices(img, colors)
+	visited := make([]bool, len(indices))
+	var stack []int
+
+	for start, index := range indices {
+		if visited[start] {
+			continue
+		}
+
+		// Flood fill the region holding the starting pixel
+		size := 0
+		visited[start] = true
+		stack = append(stack[:0], start)
+
+		for len(stack) > 0 {
+			pixel := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			size++
+
+			x, y := pixel%width, pixel/width
+			for _, neighbor := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				nx, ny := neighbor[0], neighbor[1]
+				if nx < 0 || ny < 0 || nx >= width || ny >= height {
+					continue
+				}
+
+				if next := ny*width + nx; !visited[next] && indices[next] == index {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+
+		stats[index].Count++
+		stats[index].Pixels += size
+		if size > stats[index].Largest {
+			stats[index].Largest = size
+		}
+	}
+
+	for index := range stats {
+		if stats[index].Count > 0 {
+			stats[index].Mean = float64(stats[index].Pixels) / float64(stats[index].Count)
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegions(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		img    image.Image
+		colors []color.RGBA
+		stats  []RegionStats
+	}{
+		{
+			title:  "no colors",
+			img:    testImage(2, 2, func(int, int) color.RGBA { return red }),
+			colors: []color.RGBA{},
+			stats:  []RegionStats{},
+		},
+		{
+			title:  "single region",
+			img:    testImage(3, 2, func(int, int) color.RGBA { return red }),
+			colors: []color.RGBA{red, blue},
+			stats: []RegionStats{
+				{Count: 1, Pixels: 6, Largest: 6, Mean: 6},
+				{},
+			},
+		},
+		{
+			title: "checkerboard",
+			img: testImage(3, 3, func(x int, y int) color.RGBA {
+				if (x+y)%2 == 0 {
+					return red
+				}
+				return blue
+			}),
+			colors: []color.RGBA{red, blue},
+			stats: []RegionStats{
+				{Count: 5, Pixels: 5, Largest: 1, Mean: 1},
+				{Count: 4, Pixels: 4, Largest: 1, Mean: 1},
+			},
+		},
+		{
+			title: "sky and specks",
+			img: testImage(4, 4, func(x int, y int) color.RGBA {
+				switch {
+				case y < 2:
+					return blue
+				case x%2 == 0 && y == 3:
+					return blue
+				default:
+					return green
+				}
+			}),
+			colors: []color.RGBA{blue, green},
+			stats: []RegionStats{
+				{Count: 3, Pixels: 10, Largest: 8, Mean: 10.0 / 3},
+				{Count: 1, Pixels: 6, Largest: 6, Mean: 6},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.stats, Regions(test.img, test.colors))
+
+		})
+	}
+
+}
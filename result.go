@@ -0,0 +1,293 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Result describes the palette of an image in detail.
+type Result struct {
+	// Colors holds a swatch for every distinct palette color.
+	Colors []Swatch `json:"colors" yaml:"colors"`
+
+	// Pixels is the number of pixels that were quantized.
+	Pixels int `json:"pixels" yaml:"pixels"`
+}
+
+// Swatch describes a single palette color, and how much of the image it
+// covers.
+type Swatch struct {
+	RGBA color.RGBA `json:"rgba" yaml:"rgba"`
+	Hex  string     `json:"hex" yaml:"hex"`
+	HSL  HSL        `json:"hsl" yaml:"hsl"`
+
+	// Population is the number of pixels represented by the color.
+	Population int `json:"population" yaml:"population"`
+
+	// Proportion is the fraction of the image represented by the color,
+	// within [0, 1]. For weighted pixels, this is the fraction of the total
+	// weight.
+	Proportion float64 `json:"proportion" yaml:"proportion"`
+
+	// Exemplar is the coordinate of a representative source pixel, the one
+	// closest to the color among those nearest to it, if requested. Pixels
+	// dropped by a filter or weight are never exemplars, and ties go to the
+	// heaviest pixel.
+	Exemplar *image.Point `json:"exemplar,omitempty" yaml:"exemplar,omitempty"`
+}
+
+// swatchRGBA is the encoded form of a swatch color, with lowercase keys like
+// every other field.
+type swatchRGBA struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	A uint8 `json:"a"`
+}
+
+// swatchPoint is the encoded form of a swatch exemplar, with lowercase keys
+// like every other field.
+type swatchPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// MarshalJSON encodes the swatch, with lowercase keys for the components of
+// its color and exemplar.
+func (s Swatch) MarshalJSON() ([]byte, error) {
+	type plain Swatch
+	clr := s.RGBA
+
+	var exemplar *swatchPoint
+	if s.Exemplar != nil {
+		exemplar = &swatchPoint{s.Exemplar.X, s.Exemplar.Y}
+	}
+
+	return json.Marshal(struct {
+		RGBA swatchRGBA `json:"rgba"`
+		plain
+		Exemplar *swatchPoint `json:"exemplar,omitempty"`
+	}{swatchRGBA{clr.R, clr.G, clr.B, clr.A}, plain(s), exemplar})
+}
+
+// UnmarshalJSON decodes a swatch previously encoded with MarshalJSON.
+func (s *Swatch) UnmarshalJSON(data []byte) error {
+	type plain Swatch
+	encoded := struct {
+		RGBA swatchRGBA `json:"rgba"`
+		*plain
+		Exemplar *swatchPoint `json:"exemplar"`
+	}{plain: (*plain)(s)}
+
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	clr := encoded.RGBA
+	s.RGBA = color.RGBA{clr.R, clr.G, clr.B, clr.A}
+
+	s.Exemplar = nil
+	if point := encoded.Exemplar; point != nil {
+		s.Exemplar = &image.Point{point.X, point.Y}
+	}
+
+	return nil
+}
+
+// newSwatch returns a swatch for the given color.
+func newSwatch(clr color.RGBA, population int, proportion float64) Swatch {
+	return Swatch{
+		RGBA:       clr,
+		Hex:        Hex(clr),
+		HSL:        ToHSL(clr),
+		Population: population,
+		Proportion: proportion,
+	}
+}
+
+// Analyze takes in an image, and performs MMCQ to the specified number of
+// levels, returning a swatch for every distinct palette color in order of
+// decreasing proportion. Levels outside of [0, MaxLevels] are clamped.
+func Analyze(img image.Image, levels int) Result {
+	var quantizer Quantizer
+	result, _ := quantizer.Analyze(img, clampLevels(levels))
+	return result
+}
+
+// Analyze takes in an image, and performs MMCQ to the specified number of
+// levels. Rather than a palette, it returns a swatch for every distinct
+// palette color, in order of decreasing proportion. Palettes are never padded,
+// and identical colors from different partitions are merged. Returns
+// ErrInvalidLevels if levels is not within [0, MaxLevels].
+func (q *Quantizer) Analyze(img image.Image, levels int) (Result, error) {
+
+	if levels < 0 || levels > MaxLevels {
+		return Result{}, ErrInvalidLevels
+	}
+
+	q.extractHistogram(img, img.Bounds())
+	partitions := q.partition(q.pixels, q.weights, levels, 1<<uint(levels))
+	defer q.release()
+
+	// Paletted images may be reduced to a histogram, where each pixel stands
+	// for as many pixels as its weight
+	population := func(partition box) int {
+		if q.counted {
+			return int(partition.population)
+		}
+		return len(partition.pixels)
+	}
+
+	var total float64
+	result := Result{Colors: []Swatch{}}
+	for _, partition := range partitions {
+		total += partition.population
+		result.Pixels += population(partition)
+	}
+
+	indices := map[color.RGBA]int{}
+
+	for _, partition := range partitions {
+		if len(partition.pixels) == 0 {
+			continue
+		}
+
+		clr := partition.average(q.truncate)
+
+		index, found := indices[clr]
+		if !found {
+			index = len(result.Colors)
+			indices[clr] = index
+			result.Colors = append(result.Colors, newSwatch(clr, 0, 0))
+		}
+
+		result.Colors[index].Population += population(partition)
+		result.Colors[index].Proportion += partition.population / total
+	}
+
+	sort.SliceStable(result.Colors, func(i int, j int) bool {
+		return result.Colors[i].Proportion > result.Colors[j].Proportion
+	})
+
+	if q.exemplars {
+		// The pixel buffer has been reordered, so the pixels are extracted
+		// again to recover their coordinates
+		colors := make([]color.RGBA, len(result.Colors))
+		for index, swatch := range result.Colors {
+			colors[index] = swatch.RGBA
+		}
+
+		var exemplars []*image.Point
+		q.phase(phaseRemap, func() {
+			_, exemplars = assign(img, colors, q.filter, q.weight)
+		})
+
+		for index := range result.Colors {
+			result.Colors[index].Exemplar = exemplars[index]
+		}
+	}
+
+	return result, nil
+}
+
+// Measure takes in an image and a palette, such as one returned by Image or a
+// fixed palette, and returns a swatch for every palette color in the same
+// order, counting each pixel towards the nearest palette color. When a color
+// appears more than once in the palette, only the first is counted. Every
+// swatch that any pixel is counted towards includes an exemplar.
+func Measure(img image.Image, colors []color.RGBA) Result {
+
+	counts, exemplars := assign(img, colors, nil, nil)
+	pixels := img.Bounds().Dx() * img.Bounds().Dy()
+
+	result := Result{Colors: make([]Swatch, len(colors)), Pixels: pixels}
+
+	for index, clr := range colors {
+		var proportion float64
+		if pixels > 0 {
+			proportion = float64(counts[index]) / float64(pixels)
+		}
+		result.Colors[index] = newSwatch(clr, counts[index], proportion)
+		result.Colors[index].Exemplar = exemplars[index]
+	}
+
+	return result
+}
+
+// assign counts every pixel of the given image that passes the given filter,
+// if any, towards its nearest palette color. Pixels are weighted like they are
+// when quantizing, so those with a weight of zero or less are dropped. Returns
+// the count for each color, along with the coordinate of the pixel closest to
+// each color among those counted towards it, preferring the heaviest pixel
+// when several are equally close.
+func assign(img image.Image, colors []color.RGBA, filter func(x int, y int, c color.RGBA) bool, weight func(x int, y int, c color.RGBA) float64) ([]int, []*image.Point) {
+
+	counts := make([]int, len(colors))
+	exemplars := make([]*image.Point, len(colors))
+	distances := make([]int, len(colors))
+	weights := make([]float64, len(colors))
+
+	if len(colors) == 0 {
+		return counts, exemplars
+	}
+
+	rect := img.Bounds()
+	height := rect.Dy()
+
+	// Pixels are extracted column by column, so their coordinates follow from
+	// their index alone
+	for index, pixel := range extract(nil, img, rect) {
+		x, y := rect.Min.X+index/height, rect.Min.Y+index%height
+
+		if filter != nil && !filter(x, y, pixel) {
+			continue
+		}
+
+		heft := 1.0
+		if weight != nil {
+			// Negated, so that NaN weights are also dropped
+			if heft = weight(x, y, pixel); !(heft > 0) {
+				continue
+			}
+		}
+
+		nearest, distance := nearest(colors, pixel)
+		counts[nearest]++
+
+		closer := exemplars[nearest] == nil || distance < distances[nearest]
+		heavier := distance == distances[nearest] && heft > weights[nearest]
+
+		if closer || heavier {
+			exemplars[nearest] = &image.Point{x, y}
+			distances[nearest] = distance
+			weights[nearest] = heft
+		}
+	}
+
+	return counts, exemplars
+}
+
+// nearest returns the index of the palette color closest to the given pixel,
+// along with its squared distance in RGB. Ties are broken in favor of the
+// first color.
+func nearest(colors []color.RGBA, pixel color.RGBA) (int, int) {
+	best, bestDistance := 0, -1
+
+	for index, clr := range colors {
+		r := int(clr.R) - int(pixel.R)
+		g := int(clr.G) - int(pixel.G)
+		b := int(clr.B) - int(pixel.B)
+
+		if distance := r*r + g*g + b*b; bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = index, distance
+		}
+	}
+
+	return best, bestDistance
+}
@@ -0,0 +1,216 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	resultRed  = color.RGBA{255, 0, 0, 0xFF}
+	resultBlue = color.RGBA{0, 0, 255, 0xFF}
+)
+
+// threeQuarters returns an image that is three quarters red and one quarter
+// blue.
+func threeQuarters(x int, _ int) color.RGBA {
+	if x < 3 {
+		return resultRed
+	}
+	return resultBlue
+}
+
+func TestAnalyze(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		levels int
+		colors []Swatch
+	}{
+		{
+			title:  "zero levels",
+			levels: 0,
+			colors: []Swatch{
+				newSwatch(color.RGBA{191, 0, 64, 0xFF}, 16, 1),
+			},
+		},
+		{
+			title:  "one level",
+			levels: 1,
+			colors: []Swatch{
+				newSwatch(color.RGBA{128, 0, 128, 0xFF}, 8, 0.5),
+				newSwatch(resultRed, 8, 0.5),
+			},
+		},
+		{
+			title:  "merged partitions",
+			levels: 3,
+			colors: []Swatch{
+				newSwatch(resultRed, 12, 0.75),
+				newSwatch(resultBlue, 4, 0.25),
+			},
+		},
+	}
+
+	img := testImage(4, 4, threeQuarters)
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			result := Analyze(img, test.levels)
+
+			assert.Equal(t, 16, result.Pixels)
+			assert.Equal(t, test.colors, result.Colors)
+
+		})
+	}
+
+	var quantizer Quantizer
+	_, err := quantizer.Analyze(img, -1)
+	assert.Equal(t, ErrInvalidLevels, err)
+
+	result := Analyze(testImage(0, 0, threeQuarters), 2)
+	assert.Equal(t, Result{Colors: []Swatch{}}, result)
+
+}
+
+func TestMeasure(t *testing.T) {
+
+	img := testImage(4, 4, threeQuarters)
+
+	result := Measure(img, []color.RGBA{resultBlue, {200, 0, 0, 0xFF}, resultBlue})
+
+	blue := newSwatch(resultBlue, 4, 0.25)
+	blue.Exemplar = &image.Point{3, 0}
+	red := newSwatch(color.RGBA{200, 0, 0, 0xFF}, 12, 0.75)
+	red.Exemplar = &image.Point{0, 0}
+
+	assert.Equal(t, Result{
+		Colors: []Swatch{
+			blue,
+			red,
+			newSwatch(resultBlue, 0, 0),
+		},
+		Pixels: 16,
+	}, result)
+
+	assert.Equal(t, Result{Colors: []Swatch{}, Pixels: 16}, Measure(img, []color.RGBA{}))
+
+}
+
+func TestAnalyzePaletted(t *testing.T) {
+
+	// Columns of red and blue, using only two of four palette colors
+	img := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{
+		color.RGBA{0, 255, 0, 0xFF},
+		resultRed,
+		resultBlue,
+		color.RGBA{0, 0, 0, 0xFF},
+	})
+	for index := range img.Pix {
+		img.Pix[index] = uint8(1 + index%4/3)
+	}
+
+	result := Analyze(img, 2)
+
+	assert.Equal(t, Result{
+		Colors: []Swatch{
+			newSwatch(resultRed, 12, 0.75),
+			newSwatch(resultBlue, 4, 0.25),
+		},
+		Pixels: 16,
+	}, result)
+
+	// Analyze and Image agree on the palette of the same image
+	palette, err := NewQuantizer(WithAllowShortPalette()).Image(img, 2)
+	require.Nil(t, err)
+	assert.Len(t, palette, 2)
+	assert.Contains(t, palette, resultRed)
+	assert.Contains(t, palette, resultBlue)
+
+}
+
+func TestAnalyzeExemplars(t *testing.T) {
+
+	// A gradient from dark to light red on the left, and solid blue on the
+	// right
+	img := testImage(4, 4, func(x int, y int) color.RGBA {
+		if x < 2 {
+			return color.RGBA{uint8(100 + x*40 + y*20), 0, 0, 0xFF}
+		}
+		return resultBlue
+	})
+
+	result, err := NewQuantizer(WithExemplars()).Analyze(img, 1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(result.Colors))
+
+	// The average red is 150, and the first of the closest pixels is at (0, 2)
+	assert.Equal(t, color.RGBA{150, 0, 0, 0xFF}, result.Colors[0].RGBA)
+	assert.Equal(t, &image.Point{0, 2}, result.Colors[0].Exemplar)
+	assert.Equal(t, &image.Point{2, 0}, result.Colors[1].Exemplar)
+
+	// A filtered pixel is never an exemplar
+	quantizer := NewQuantizer(WithExemplars(), WithPixelFilter(func(x int, y int, _ color.RGBA) bool {
+		return x != 2
+	}))
+	result, err = quantizer.Analyze(img, 1)
+	require.Nil(t, err)
+	assert.Equal(t, &image.Point{3, 0}, result.Colors[1].Exemplar)
+
+	// A pixel without weight is never an exemplar, and the heaviest of the
+	// equally close pixels is chosen
+	quantizer = NewQuantizer(WithExemplars(), WithPixelWeight(func(x int, y int, _ color.RGBA) float64 {
+		switch {
+		case x == 0:
+			return 0
+		case x == 2 && y == 1:
+			return 3
+		default:
+			return 1
+		}
+	}))
+	result, err = quantizer.Analyze(testImage(4, 4, func(int, int) color.RGBA {
+		return resultBlue
+	}), 0)
+	require.Nil(t, err)
+	assert.Equal(t, &image.Point{2, 1}, result.Colors[0].Exemplar)
+
+	// Exemplars are omitted by default
+	for _, swatch := range Analyze(img, 1).Colors {
+		assert.Nil(t, swatch.Exemplar)
+	}
+
+}
+
+func TestResultJSON(t *testing.T) {
+
+	swatch := newSwatch(color.RGBA{0xFF, 0x00, 0x00, 0xFF}, 3, 0.75)
+	swatch.Exemplar = &image.Point{1, 2}
+
+	result := Result{
+		Colors: []Swatch{swatch},
+		Pixels: 4,
+	}
+
+	data, err := json.Marshal(result)
+	require.Nil(t, err)
+
+	assert.Equal(t, `{"colors":[{"rgba":{"r":255,"g":0,"b":0,"a":255},"hex":"#FF0000","hsl":{"h":0,"s":1,"l":0.5},"population":3,"proportion":0.75,"exemplar":{"x":1,"y":2}}],"pixels":4}`, string(data))
+
+	var decoded Result
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, result, decoded)
+
+}
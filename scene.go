@@ -0,0 +1,90 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// SceneDetector flags the frames of a stream where the palette changes
+// sharply from the previous frame, for simple shot boundary detection.
+type SceneDetector struct {
+	// Threshold is the palette distance from the previous frame, as given by
+	// PaletteDistance, above which a frame begins a new scene.
+	Threshold float64
+
+	// Levels is the number of levels used to quantize every frame.
+	Levels int
+
+	previous []color.RGBA
+}
+
+// NewSceneDetector returns a scene detector with the given threshold, that
+// quantizes frames to the given number of levels.
+func NewSceneDetector(threshold float64, levels int) *SceneDetector {
+	return &SceneDetector{Threshold: threshold, Levels: levels}
+}
+
+// Next quantizes the next frame of the stream, and returns whether it begins a
+// new scene, along with the palette distance from the previous frame.
+func (d *SceneDetector) Next(frame image.Image) (bool, float64) {
+	return d.NextPalette(Image(frame, d.Levels))
+}
+
+// NextPalette takes the palette of the next frame of the stream, such as one
+// accumulated by a Builder, and returns whether it begins a new scene, along
+// with the palette distance from the previous frame. The first frame never
+// begins a new scene.
+func (d *SceneDetector) NextPalette(colors []color.RGBA) (bool, float64) {
+	previous := d.previous
+	d.previous = append([]color.RGBA{}, colors...)
+
+	if previous == nil {
+		return false, 0
+	}
+
+	distance := PaletteDistance(previous, colors)
+	return distance > d.Threshold, distance
+}
+
+// Reset forgets the previous frame, so that the next frame is treated as the
+// first of a new stream.
+func (d *SceneDetector) Reset() {
+	d.previous = nil
+}
+
+// PaletteDistance returns the perceptual distance between two palettes, as the
+// average DeltaE from each color to the nearest color of the other palette,
+// taken in both directions. Identical palettes have a distance of 0, no matter
+// their order. The distance between an empty and a non-empty palette is
+// infinite.
+func PaletteDistance(first []color.RGBA, second []color.RGBA) float64 {
+	switch {
+	case len(first) == 0 && len(second) == 0:
+		return 0
+	case len(first) == 0 || len(second) == 0:
+		return math.Inf(1)
+	}
+
+	return (nearestDeltaE(first, second) + nearestDeltaE(second, first)) / 2
+}
+
+// nearestDeltaE returns the average DeltaE from each of the given colors to the
+// nearest of the other colors.
+func nearestDeltaE(colors []color.RGBA, others []color.RGBA) float64 {
+	var total float64
+
+	for _, clr := range colors {
+		nearest := math.Inf(1)
+		for _, other := range others {
+			nearest = math.Min(nearest, DeltaE(clr, other))
+		}
+		total += nearest
+	}
+
+	return total / float64(len(colors))
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaletteDistance(t *testing.T) {
+
+	tests := []struct {
+		title    string
+		first    []color.RGBA
+		second   []color.RGBA
+		distance float64
+	}{
+		{
+			title:    "both empty",
+			distance: 0,
+		},
+		{
+			title:    "one empty",
+			first:    []color.RGBA{red},
+			distance: math.Inf(1),
+		},
+		{
+			title:    "identical palettes in a different order",
+			first:    []color.RGBA{red, green, blue},
+			second:   []color.RGBA{blue, red, green},
+			distance: 0,
+		},
+		{
+			title:    "one extra color",
+			first:    []color.RGBA{red, green},
+			second:   []color.RGBA{red, green, blue},
+			distance: math.Min(DeltaE(blue, red), DeltaE(blue, green)) / 3 / 2,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, test.distance, PaletteDistance(test.first, test.second), 1e-9)
+			assert.InDelta(t, test.distance, PaletteDistance(test.second, test.first), 1e-9)
+		})
+	}
+}
+
+func TestSceneDetector(t *testing.T) {
+
+	solid := func(clr color.RGBA) image.Image {
+		return testImage(4, 4, func(int, int) color.RGBA { return clr })
+	}
+
+	frames := []struct {
+		frame image.Image
+		scene bool
+	}{
+		{solid(red), false},
+		{solid(red), false},
+		{solid(color.RGBA{250, 0, 0, 0xFF}), false},
+		{solid(blue), true},
+		{solid(blue), false},
+		{solid(white), true},
+	}
+
+	detector := NewSceneDetector(10, 1)
+
+	for index, test := range frames {
+		name := fmt.Sprintf("Case #%d - frame", index)
+		t.Run(name, func(t *testing.T) {
+			scene, distance := detector.Next(test.frame)
+			assert.Equal(t, test.scene, scene)
+			assert.Equal(t, test.scene, distance > 10)
+		})
+	}
+
+	// After a reset, the next frame is treated as the first
+	detector.Reset()
+	scene, distance := detector.Next(solid(red))
+	assert.False(t, scene)
+	assert.Equal(t, 0.0, distance)
+}
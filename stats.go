@@ -0,0 +1,122 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Statistics holds summary measurements of the colors in an image.
+type Statistics struct {
+	// Mean is the average color across all pixels, rounded to the nearest
+	// value.
+	Mean color.RGBA
+
+	// StdDevR, StdDevG, & StdDevB are the standard deviations of the red,
+	// green, & blue components across all pixels.
+	StdDevR float64
+	StdDevG float64
+	StdDevB float64
+
+	// Colorfulness is the Hasler & Süsstrunk colorfulness metric, where 0 is
+	// a grayscale image and values above 100 are extremely colorful.
+	Colorfulness float64
+
+	// Saturation is the mean HSV saturation across all pixels, within [0, 1].
+	Saturation float64
+
+	// Luminance is the mean relative luminance across all pixels, within
+	// [0, 1].
+	Luminance float64
+
+	// Contrast is the RMS contrast, the standard deviation of the relative
+	// luminance across all pixels, within [0, 0.5].
+	Contrast float64
+}
+
+// Stats takes in an image, and returns the mean color, per-component standard
+// deviation, colorfulness, mean saturation, mean luminance, and contrast of its
+// pixels, computed in a single pass.
+func Stats(img image.Image) Statistics {
+
+	var (
+		count            float64
+		sumR, sumG, sumB float64
+		sqR, sqG, sqB    float64
+		sumRG, sumYB     float64
+		sqRG, sqYB       float64
+		sumS             float64
+		sumL, sqL        float64
+	)
+
+	rect := img.Bounds()
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+
+			r, g, b, _ := img.At(x, y).RGBA()
+			fr, fg, fb := float64(r>>8), float64(g>>8), float64(b>>8)
+
+			count++
+
+			sumR, sumG, sumB = sumR+fr, sumG+fg, sumB+fb
+			sqR, sqG, sqB = sqR+fr*fr, sqG+fg*fg, sqB+fb*fb
+
+			// Opponent color components used for colorfulness
+			rg := fr - fg
+			yb := (fr+fg)/2 - fb
+
+			sumRG, sumYB = sumRG+rg, sumYB+yb
+			sqRG, sqYB = sqRG+rg*rg, sqYB+yb*yb
+
+			pixel := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xFF}
+			luminance := Luminance(pixel)
+
+			sumS += ToHSV(pixel).S
+			sumL, sqL = sumL+luminance, sqL+luminance*luminance
+		}
+	}
+
+	if count == 0 {
+		return Statistics{Mean: color.RGBA{0, 0, 0, 0xFF}}
+	}
+
+	meanR, meanG, meanB := sumR/count, sumG/count, sumB/count
+	meanRG, meanYB := sumRG/count, sumYB/count
+
+	stdRG := deviation(sqRG, meanRG, count)
+	stdYB := deviation(sqYB, meanYB, count)
+	meanL := sumL / count
+
+	// The sums are whole numbers, so the mean color is rounded exactly as
+	// palette averages are
+	mean := average(uint64(sumR), uint64(sumG), uint64(sumB), uint64(count), false)
+
+	return Statistics{
+		Mean:    mean,
+		StdDevR: deviation(sqR, meanR, count),
+		StdDevG: deviation(sqG, meanG, count),
+		StdDevB: deviation(sqB, meanB, count),
+		Colorfulness: math.Sqrt(stdRG*stdRG+stdYB*stdYB) +
+			0.3*math.Sqrt(meanRG*meanRG+meanYB*meanYB),
+		Saturation: sumS / count,
+		Luminance:  meanL,
+		Contrast:   deviation(sqL, meanL, count),
+	}
+}
+
+// deviation returns the population standard deviation given the sum of
+// squares, mean, and count of a set of values.
+func deviation(squares float64, mean float64, count float64) float64 {
+	variance := squares/count - mean*mean
+
+	// Guard against tiny negative values caused by floating point error
+	if variance < 0 {
+		return 0
+	}
+	return math.Sqrt(variance)
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testImage builds an image of the given size, with every pixel colored by
+// the given function.
+func testImage(width int, height int, pixel func(x int, y int) color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, pixel(x, y))
+		}
+	}
+
+	return img
+}
+
+func TestStats(t *testing.T) {
+
+	tests := []struct {
+		title string
+		img   image.Image
+		stats Statistics
+	}{
+		{
+			title: "empty image",
+			img:   image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			stats: Statistics{
+				Mean: color.RGBA{0, 0, 0, 0xFF},
+			},
+		},
+		{
+			title: "uniform image",
+			img: testImage(4, 4, func(int, int) color.RGBA {
+				return color.RGBA{105, 105, 105, 0xFF}
+			}),
+			stats: Statistics{
+				Mean:      color.RGBA{105, 105, 105, 0xFF},
+				Luminance: 0.14126,
+			},
+		},
+		{
+			title: "black and white",
+			img: testImage(4, 4, func(x int, _ int) color.RGBA {
+				if x < 2 {
+					return color.RGBA{0, 0, 0, 0xFF}
+				}
+				return color.RGBA{255, 255, 255, 0xFF}
+			}),
+			stats: Statistics{
+				Mean:      color.RGBA{128, 128, 128, 0xFF},
+				StdDevR:   127.5,
+				StdDevG:   127.5,
+				StdDevB:   127.5,
+				Luminance: 0.5,
+				Contrast:  0.5,
+			},
+		},
+		{
+			title: "red and green",
+			img: testImage(4, 4, func(_ int, y int) color.RGBA {
+				if y < 2 {
+					return color.RGBA{255, 0, 0, 0xFF}
+				}
+				return color.RGBA{0, 255, 0, 0xFF}
+			}),
+			stats: Statistics{
+				Mean:         color.RGBA{128, 128, 0, 0xFF},
+				StdDevR:      127.5,
+				StdDevG:      127.5,
+				Colorfulness: 293.25,
+				Saturation:   1,
+				Luminance:    0.4639,
+				Contrast:     0.2513,
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			stats := Stats(test.img)
+
+			assert.Equal(t, test.stats.Mean, stats.Mean)
+			assert.InDelta(t, test.stats.StdDevR, stats.StdDevR, 0.001)
+			assert.InDelta(t, test.stats.StdDevG, stats.StdDevG, 0.001)
+			assert.InDelta(t, test.stats.StdDevB, stats.StdDevB, 0.001)
+			assert.InDelta(t, test.stats.Colorfulness, stats.Colorfulness, 0.001)
+			assert.InDelta(t, test.stats.Saturation, stats.Saturation, 0.001)
+			assert.InDelta(t, test.stats.Luminance, stats.Luminance, 0.001)
+			assert.InDelta(t, test.stats.Contrast, stats.Contrast, 0.001)
+
+		})
+	}
+
+}
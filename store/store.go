@@ -0,0 +1,123 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package store defines a pluggable store of palettes, so that applications
+// embedding the library can search their images by color.
+//
+// Only an in-memory store is provided. Persistent stores, such as one backed
+// by SQLite, are out of scope for this package, since they would pull a
+// database driver into every program using the library. Applications that
+// need one implement PaletteStore over their own database instead.
+package store
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/joshdk/quantize"
+)
+
+// PaletteStore stores the palettes of images under arbitrary keys, such as
+// file paths or database identifiers.
+type PaletteStore interface {
+	// Put stores the given palette under the given key, replacing any palette
+	// already stored under it.
+	Put(key string, colors []color.RGBA) error
+
+	// Get returns the palette stored under the given key, and whether one was
+	// found.
+	Get(key string) ([]color.RGBA, bool, error)
+
+	// Query returns every stored palette holding a color within the given
+	// perceptual distance of the given color, closest first. The tolerance
+	// must be a non-negative number, otherwise ErrInvalidTolerance is
+	// returned.
+	Query(clr color.RGBA, tolerance float64) ([]Match, error)
+}
+
+// ErrInvalidTolerance is returned when a query tolerance is negative or NaN.
+var ErrInvalidTolerance = errors.New("tolerance must be a non-negative number")
+
+// Match is a palette found by a query.
+type Match struct {
+	// Key is the key the palette is stored under.
+	Key string `json:"key"`
+
+	// Distance is the perceptual distance between the queried color and the
+	// closest color in the palette.
+	Distance float64 `json:"distance"`
+}
+
+// Memory is a PaletteStore held in memory. The zero value is ready to use,
+// and a Memory is safe for concurrent use.
+type Memory struct {
+	mutex    sync.RWMutex
+	palettes map[string][]color.RGBA
+}
+
+// NewMemory returns an empty in-memory PaletteStore.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Put stores a copy of the given palette under the given key.
+func (m *Memory) Put(key string, colors []color.RGBA) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.palettes == nil {
+		m.palettes = make(map[string][]color.RGBA)
+	}
+	m.palettes[key] = append([]color.RGBA{}, colors...)
+
+	return nil
+}
+
+// Get returns a copy of the palette stored under the given key.
+func (m *Memory) Get(key string) ([]color.RGBA, bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	colors, found := m.palettes[key]
+	if !found {
+		return nil, false, nil
+	}
+
+	return append([]color.RGBA{}, colors...), true, nil
+}
+
+// Query scans every stored palette for colors near the given color. Matches
+// at the same distance are ordered by key.
+func (m *Memory) Query(clr color.RGBA, tolerance float64) ([]Match, error) {
+	if !(tolerance >= 0) {
+		return nil, ErrInvalidTolerance
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	matches := []Match{}
+	for key, colors := range m.palettes {
+		distance := math.Inf(1)
+		for _, other := range colors {
+			distance = math.Min(distance, quantize.DeltaE(clr, other))
+		}
+
+		if distance <= tolerance {
+			matches = append(matches, Match{key, distance})
+		}
+	}
+
+	sort.Slice(matches, func(i int, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Key < matches[j].Key
+	})
+
+	return matches, nil
+}
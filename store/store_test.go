@@ -0,0 +1,108 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package store
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+
+	var _ PaletteStore = NewMemory()
+
+	var memory Memory
+
+	require.Nil(t, memory.Put("red", []color.RGBA{{255, 0, 0, 0xFF}, {0, 0, 0, 0xFF}}))
+	require.Nil(t, memory.Put("orange", []color.RGBA{{255, 102, 0, 0xFF}}))
+	require.Nil(t, memory.Put("blue", []color.RGBA{{0, 0, 255, 0xFF}}))
+	require.Nil(t, memory.Put("dark red", []color.RGBA{{250, 0, 0, 0xFF}}))
+
+	// Stored palettes are copies, unaffected by later changes to the original
+	colors := []color.RGBA{{0, 255, 0, 0xFF}}
+	require.Nil(t, memory.Put("green", colors))
+	colors[0] = color.RGBA{0, 0, 0, 0xFF}
+
+	tests := []struct {
+		title     string
+		clr       color.RGBA
+		tolerance float64
+		keys      []string
+	}{
+		{
+			title:     "exact color",
+			clr:       color.RGBA{0, 0, 255, 0xFF},
+			tolerance: 0,
+			keys:      []string{"blue"},
+		},
+		{
+			title:     "closest first",
+			clr:       color.RGBA{255, 0, 0, 0xFF},
+			tolerance: 50,
+			keys:      []string{"red", "dark red", "orange"},
+		},
+		{
+			title:     "copied palette",
+			clr:       color.RGBA{0, 255, 0, 0xFF},
+			tolerance: 1,
+			keys:      []string{"green"},
+		},
+		{
+			title:     "no matches",
+			clr:       color.RGBA{255, 255, 0, 0xFF},
+			tolerance: 1,
+			keys:      []string{},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			matches, err := memory.Query(test.clr, test.tolerance)
+			require.Nil(t, err)
+
+			keys := []string{}
+			for _, match := range matches {
+				keys = append(keys, match.Key)
+				assert.True(t, match.Distance <= test.tolerance)
+			}
+			assert.Equal(t, test.keys, keys)
+
+		})
+	}
+
+	palette, found, err := memory.Get("orange")
+	require.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []color.RGBA{{255, 102, 0, 0xFF}}, palette)
+
+	// Replacing a palette
+	require.Nil(t, memory.Put("orange", []color.RGBA{{255, 128, 0, 0xFF}}))
+	palette, _, _ = memory.Get("orange")
+	assert.Equal(t, []color.RGBA{{255, 128, 0, 0xFF}}, palette)
+
+	_, found, err = memory.Get("purple")
+	require.Nil(t, err)
+	assert.False(t, found)
+
+	// Negative and NaN tolerances are rejected
+	_, err = memory.Query(color.RGBA{255, 0, 0, 0xFF}, -1)
+	assert.Equal(t, ErrInvalidTolerance, err)
+	_, err = memory.Query(color.RGBA{255, 0, 0, 0xFF}, math.NaN())
+	assert.Equal(t, ErrInvalidTolerance, err)
+
+	// An infinite tolerance matches every palette
+	matches, err := memory.Query(color.RGBA{255, 0, 0, 0xFF}, math.Inf(1))
+	require.Nil(t, err)
+	assert.Len(t, matches, 5)
+
+}
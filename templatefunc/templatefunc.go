@@ -0,0 +1,152 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package templatefunc provides template functions for quantizing images, so
+// that server side rendered pages can be themed around the images they show.
+package templatefunc
+
+import (
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joshdk/quantize"
+)
+
+// entry is the cached palette of a single image.
+type entry struct {
+	modTime time.Time
+	colors  []color.RGBA
+}
+
+// Funcs quantizes the images within a file system on behalf of templates,
+// caching the palette of every image until it is modified. A Funcs is safe for
+// concurrent use, as templates may be executed concurrently.
+type Funcs struct {
+	fs     http.FileSystem
+	levels int
+
+	mutex sync.Mutex
+	cache map[string]entry
+}
+
+// New returns template functions that quantize the images within the given
+// file system, such as http.Dir("static"), to the given number of levels.
+// Levels outside of [0, MaxLevels] are clamped.
+func New(fs http.FileSystem, levels int) *Funcs {
+	return &Funcs{
+		fs:     fs,
+		levels: levels,
+		cache:  map[string]entry{},
+	}
+}
+
+// FuncMap returns the template functions, to be passed to Template.Funcs:
+//
+//	paletteOf "photo.jpg"           palette hex colors, dominant first
+//	dominantOf "photo.jpg"          dominant hex color
+//	contrastText (dominantOf "...") black or white hex, whichever is readable
+//
+// Images are named by their path within the file system, and are decoded
+// with image.Decode, so the decoder for every image format must be registered.
+func (f *Funcs) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"paletteOf":    f.PaletteOf,
+		"dominantOf":   f.DominantOf,
+		"contrastText": ContrastText,
+	}
+}
+
+// PaletteOf returns the palette of the named image as hex colors, in order of
+// decreasing proportion.
+func (f *Funcs) PaletteOf(name string) ([]string, error) {
+	colors, err := f.colors(name)
+	if err != nil {
+		return nil, err
+	}
+
+	hexes := make([]string, len(colors))
+	for index, clr := range colors {
+		hexes[index] = quantize.Hex(clr)
+	}
+
+	return hexes, nil
+}
+
+// DominantOf returns the dominant color of the named image as a hex color.
+func (f *Funcs) DominantOf(name string) (string, error) {
+	colors, err := f.colors(name)
+	if err != nil {
+		return "", err
+	}
+
+	if len(colors) == 0 {
+		return "", fmt.Errorf("%s: image is empty", name)
+	}
+
+	return quantize.Hex(colors[0]), nil
+}
+
+// ContrastText takes a hex color, such as a background, and returns either
+// black or white as a hex color, whichever has the greater contrast ratio
+// against it.
+func ContrastText(hex string) (string, error) {
+	background, err := quantize.ParseHex(hex)
+	if err != nil {
+		return "", err
+	}
+
+	black := color.RGBA{0, 0, 0, 0xFF}
+	white := color.RGBA{255, 255, 255, 0xFF}
+
+	if quantize.Contrast(background, black) > quantize.Contrast(background, white) {
+		return quantize.Hex(black), nil
+	}
+	return quantize.Hex(white), nil
+}
+
+// colors returns the palette of the named image, in order of decreasing
+// proportion, from the cache if the image has not been modified since.
+func (f *Funcs) colors(name string) ([]color.RGBA, error) {
+
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	cached, found := f.cache[name]
+	f.mutex.Unlock()
+
+	if found && cached.modTime.Equal(info.ModTime()) {
+		return cached.colors, nil
+	}
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err.Error())
+	}
+
+	result := quantize.Analyze(img, f.levels)
+	colors := make([]color.RGBA, len(result.Colors))
+	for index, swatch := range result.Colors {
+		colors[index] = swatch.RGBA
+	}
+
+	f.mutex.Lock()
+	f.cache[name] = entry{info.ModTime(), colors}
+	f.mutex.Unlock()
+
+	return colors, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package templatefunc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeImage writes a PNG image of the given colors, one per column, to the
+// given path.
+func writeImage(t *testing.T, path string, colors ...color.RGBA) {
+	img := image.NewRGBA(image.Rect(0, 0, len(colors), 1))
+	for x, clr := range colors {
+		img.SetRGBA(x, 0, clr)
+	}
+
+	file, err := os.Create(path)
+	require.Nil(t, err)
+	require.Nil(t, png.Encode(file, img))
+	require.Nil(t, file.Close())
+}
+
+func TestFuncMap(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "templatefunc")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	navy := color.RGBA{0, 0, 128, 0xFF}
+	yellow := color.RGBA{255, 255, 0, 0xFF}
+	writeImage(t, filepath.Join(dir, "photo.png"), navy, navy, navy, yellow)
+
+	funcs := New(http.Dir(dir), 2)
+
+	tests := []struct {
+		title  string
+		text   string
+		output string
+		err    bool
+	}{
+		{
+			title:  "palette",
+			text:   `{{range paletteOf "photo.png"}}{{.}} {{end}}`,
+			output: "#000080 #FFFF00 ",
+		},
+		{
+			title:  "dominant color",
+			text:   `{{dominantOf "photo.png"}}`,
+			output: "#000080",
+		},
+		{
+			title:  "text against the dominant color",
+			text:   `{{contrastText (dominantOf "photo.png")}}`,
+			output: "#FFFFFF",
+		},
+		{
+			title:  "text against a light color",
+			text:   `{{contrastText "#FFFF00"}}`,
+			output: "#000000",
+		},
+		{
+			title: "missing image",
+			text:  `{{dominantOf "missing.png"}}`,
+			err:   true,
+		},
+		{
+			title: "invalid color",
+			text:  `{{contrastText "navy"}}`,
+			err:   true,
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			tmpl := template.Must(template.New(name).Funcs(funcs.FuncMap()).Parse(test.text))
+
+			var buf bytes.Buffer
+			err := tmpl.Execute(&buf, nil)
+
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, test.output, buf.String())
+
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "templatefunc")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "photo.png")
+	red := color.RGBA{255, 0, 0, 0xFF}
+	blue := color.RGBA{0, 0, 255, 0xFF}
+	modified := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	writeImage(t, path, red)
+	require.Nil(t, os.Chtimes(path, modified, modified))
+
+	funcs := New(http.Dir(dir), 0)
+
+	dominant, err := funcs.DominantOf("photo.png")
+	require.Nil(t, err)
+	assert.Equal(t, "#FF0000", dominant)
+
+	// The palette is cached for as long as the image is not modified
+	writeImage(t, path, blue)
+	require.Nil(t, os.Chtimes(path, modified, modified))
+
+	dominant, err = funcs.DominantOf("photo.png")
+	require.Nil(t, err)
+	assert.Equal(t, "#FF0000", dominant)
+
+	modified = modified.Add(time.Hour)
+	require.Nil(t, os.Chtimes(path, modified, modified))
+
+	dominant, err = funcs.DominantOf("photo.png")
+	require.Nil(t, err)
+	assert.Equal(t, "#0000FF", dominant)
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+const (
+	// themeLevels is the number of MMCQ levels used to extract the colors that
+	// a theme is derived from.
+	themeLevels = 4
+
+	// themeBackground is the largest luminance of a theme background.
+	themeBackground = 0.02
+
+	// themeForeground is the smallest contrast ratio between the background
+	// and the foreground.
+	themeForeground = 7
+
+	// themeColor is the smallest contrast ratio between the background and
+	// every other color, except the dim black.
+	themeColor = 4.5
+)
+
+// themeHues holds the target hue, in degrees, of each normal ANSI color from
+// red through cyan, in slot order.
+var themeHues = [6]float64{0, 120, 60, 240, 300, 180}
+
+// Theme is a sixteen color terminal color scheme.
+type Theme struct {
+	Background color.RGBA
+	Foreground color.RGBA
+
+	// Colors holds the 8 normal ANSI colors, followed by the 8 bright colors.
+	Colors [16]color.RGBA
+}
+
+// NewTheme takes in an image, and returns a dark terminal theme derived from
+// its palette. The background is the darkest color, darkened further, and the
+// foreground is the lightest color, lightened until it is readable against the
+// background. Each normal color from red through cyan is whichever of the six
+// most saturated remaining colors has the nearest hue, lightened as needed to
+// meet the WCAG AA contrast ratio against the background. Bright colors are lighter
+// versions of the normal colors.
+func NewTheme(img image.Image) Theme {
+	white := color.RGBA{255, 255, 255, 0xFF}
+	black := color.RGBA{0, 0, 0, 0xFF}
+
+	colors := Image(img, themeLevels)
+
+	sort.SliceStable(colors, func(i int, j int) bool {
+		return Luminance(colors[i]) < Luminance(colors[j])
+	})
+
+	var theme Theme
+
+	theme.Background = colors[0]
+	for step := 1; Luminance(theme.Background) > themeBackground; step++ {
+		theme.Background = Mix(colors[0], black, float64(step)/20)
+	}
+
+	theme.Foreground = readable(colors[len(colors)-1], theme.Background, themeForeground)
+
+	// Favor the most colorful of the remaining colors, ignoring any repeated
+	// to pad the palette
+	accents := distinct(append([]color.RGBA{}, colors[1:len(colors)-1]...))
+	sort.SliceStable(accents, func(i int, j int) bool {
+		return ToHSL(accents[i]).S > ToHSL(accents[j]).S
+	})
+	if len(accents) > 6 {
+		accents = accents[:6]
+	}
+
+	theme.Colors[0] = theme.Background
+	theme.Colors[7] = readable(Mix(theme.Foreground, theme.Background, 0.25), theme.Background, themeColor)
+	theme.Colors[8] = Mix(theme.Background, theme.Foreground, 0.3)
+	theme.Colors[15] = theme.Foreground
+
+	for index, hue := range themeHues {
+		accent := theme.Foreground
+		if len(accents) > 0 {
+			accent = nearestHue(accents, hue)
+		}
+
+		theme.Colors[index+1] = readable(accent, theme.Background, themeColor)
+		theme.Colors[index+9] = Mix(theme.Colors[index+1], white, 0.25)
+	}
+
+	return theme
+}
+
+// readable lightens the given color until its contrast ratio against the
+// given background reaches the given target.
+func readable(clr color.RGBA, background color.RGBA, target float64) color.RGBA {
+	white := color.RGBA{255, 255, 255, 0xFF}
+	result := clr
+
+	for step := 1; step <= 20 && Contrast(result, background) < target; step++ {
+		result = Mix(clr, white, float64(step)/20)
+	}
+
+	return result
+}
+
+// nearestHue returns the color whose hue is closest to the given hue, in
+// degrees, going either way around the color wheel.
+func nearestHue(colors []color.RGBA, hue float64) color.RGBA {
+	best, bestDistance := colors[0], math.Inf(1)
+
+	for _, clr := range colors {
+		distance := math.Abs(ToHSL(clr).H - hue)
+		if distance > 180 {
+			distance = 360 - distance
+		}
+
+		if distance < bestDistance {
+			best, bestDistance = clr, distance
+		}
+	}
+
+	return best
+}
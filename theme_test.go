@@ -0,0 +1,99 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTheme(t *testing.T) {
+
+	var images []image.Image
+
+	for _, name := range []string{"plush.jpg", "plush.png", "plush.gif"} {
+		file, err := os.Open(path.Join("testdata", name))
+		require.Nil(t, err)
+
+		img, _, err := image.Decode(file)
+		require.Nil(t, err)
+		require.Nil(t, file.Close())
+
+		images = append(images, img)
+	}
+
+	// Include images with too few colors to fill a theme
+	images = append(images,
+		testImage(4, 4, func(_ int, _ int) color.RGBA {
+			return color.RGBA{200, 30, 30, 0xFF}
+		}),
+		testImage(4, 4, func(x int, _ int) color.RGBA {
+			return color.RGBA{uint8(x * 60), uint8(x * 60), uint8(x * 60), 0xFF}
+		}),
+	)
+
+	for index, img := range images {
+		name := fmt.Sprintf("Case #%d - image %d", index, index)
+
+		t.Run(name, func(t *testing.T) {
+
+			theme := NewTheme(img)
+
+			assert.True(t, Luminance(theme.Background) <= themeBackground)
+			assert.True(t, Contrast(theme.Foreground, theme.Background) >= themeForeground)
+
+			assert.Equal(t, theme.Background, theme.Colors[0])
+			assert.Equal(t, theme.Foreground, theme.Colors[15])
+
+			for slot, clr := range theme.Colors {
+				if slot != 0 && slot != 8 {
+					assert.True(t, Contrast(clr, theme.Background) >= themeColor, "color %d is not readable", slot)
+				}
+			}
+
+		})
+	}
+
+}
+
+func TestNewThemeSlots(t *testing.T) {
+
+	// Stripes of each primary and secondary color, in hue order, along with
+	// a dark background and a light foreground
+	stripes := []color.RGBA{
+		{10, 10, 10, 0xFF},
+		{220, 40, 40, 0xFF},
+		{220, 220, 40, 0xFF},
+		{40, 220, 40, 0xFF},
+		{40, 220, 220, 0xFF},
+		{40, 40, 220, 0xFF},
+		{220, 40, 220, 0xFF},
+		{245, 245, 245, 0xFF},
+	}
+
+	img := testImage(len(stripes), 4, func(x int, _ int) color.RGBA {
+		return stripes[x]
+	})
+
+	theme := NewTheme(img)
+
+	for index, hue := range themeHues {
+		distance := math.Abs(ToHSL(theme.Colors[index+1]).H - hue)
+		if distance > 180 {
+			distance = 360 - distance
+		}
+
+		assert.True(t, distance < 30, "color %d has hue %f", index+1, ToHSL(theme.Colors[index+1]).H)
+	}
+
+}
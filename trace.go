@@ -0,0 +1,81 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"context"
+	"runtime/pprof"
+	"time"
+)
+
+// The phases of quantization, as named in pprof labels.
+const (
+	phaseExtract = "extract"
+	phaseSplit   = "split"
+	phaseRemap   = "remap"
+)
+
+// Trace records the time spent in each phase of quantization.
+type Trace struct {
+	// Extract is the time spent extracting pixels from images and buffers,
+	// including filtering and weighting them.
+	Extract time.Duration
+
+	// Split is the time spent partitioning pixels into boxes.
+	Split time.Duration
+
+	// Remap is the time spent assigning pixels to their nearest palette
+	// color, such as when finding exemplars.
+	Remap time.Duration
+}
+
+// WithTrace adds the time spent in each phase of quantization to the given
+// trace. Durations accumulate across calls, so that a single trace can total a
+// whole batch of images.
+func WithTrace(trace *Trace) Option {
+	return func(q *Quantizer) {
+		q.trace = trace
+	}
+}
+
+// WithProfilerLabels runs every phase of quantization with a "quantize" pprof
+// label naming it, in addition to the labels of the given context, so that CPU
+// profiles can be broken down by phase. The context should carry the labels
+// of the calling goroutine, such as the context passed to a pprof.Do
+// function, since the goroutine is left with exactly those labels after each
+// phase.
+func WithProfilerLabels(ctx context.Context) Option {
+	return func(q *Quantizer) {
+		q.labels = ctx
+	}
+}
+
+// phase runs the given function as the named phase of quantization, labeled
+// for the CPU profiler if requested, and records its duration if tracing.
+func (q *Quantizer) phase(name string, fn func()) {
+	start := time.Now()
+
+	if q.labels != nil {
+		pprof.Do(q.labels, pprof.Labels("quantize", name), func(context.Context) {
+			fn()
+		})
+	} else {
+		fn()
+	}
+
+	if q.trace == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	switch name {
+	case phaseExtract:
+		q.trace.Extract += elapsed
+	case phaseSplit:
+		q.trace.Split += elapsed
+	case phaseRemap:
+		q.trace.Remap += elapsed
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"context"
+	"image/color"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace(t *testing.T) {
+
+	img := testImage(256, 256, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x), uint8(y), uint8(x ^ y), 0xFF}
+	})
+
+	var trace Trace
+	quantizer := NewQuantizer(WithTrace(&trace))
+
+	_, err := quantizer.Image(img, 4)
+	require.Nil(t, err)
+
+	assert.True(t, trace.Extract > 0)
+	assert.True(t, trace.Split > 0)
+	assert.Equal(t, int64(0), int64(trace.Remap))
+
+	// Durations accumulate across calls, and remapping is only needed to find
+	// exemplars
+	first := trace
+	_, err = NewQuantizer(WithTrace(&trace), WithExemplars()).Analyze(img, 4)
+	require.Nil(t, err)
+
+	assert.True(t, trace.Extract > first.Extract)
+	assert.True(t, trace.Split > first.Split)
+	assert.True(t, trace.Remap > 0)
+}
+
+func TestProfilerLabels(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), 0, 0xFF}
+	})
+
+	// Labeled phases produce the same palette
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("job", "test"))
+	colors, err := NewQuantizer(WithProfilerLabels(ctx)).Image(img, 3)
+	require.Nil(t, err)
+	assert.Equal(t, Image(img, 3), colors)
+}
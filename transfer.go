@@ -0,0 +1,218 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// TransferPalette takes in an image and a target palette, and returns a copy
+// of the image restyled with the target palette. The image is quantized to as
+// many colors as the target palette holds, and its colors are matched to the
+// target colors by rank of luminance, so that the darkest color becomes the
+// darkest target color and so on. Every pixel keeps its offset from its own
+// palette color, preserving detail within each cluster. An empty target
+// palette leaves the image unchanged.
+func TransferPalette(img image.Image, target []color.RGBA) *image.RGBA {
+	rect := img.Bounds()
+	restyled := image.NewRGBA(rect)
+
+	if len(target) > MaxColors {
+		target = target[:MaxColors]
+	}
+
+	var source, mapped []color.RGBA
+	if len(target) > 0 {
+		source = Colors(img, len(target))
+		mapped = make([]color.RGBA, len(source))
+
+		ranks := byLuminance(target)
+		for rank, index := range byLuminance(source) {
+			mapped[index] = target[ranks[rank]]
+		}
+	}
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			pixel := rgbaAt(img, x, y)
+
+			if len(source) > 0 {
+				index, _ := nearest(source, pixel)
+				pixel = offset(pixel, source[index], mapped[index])
+			}
+
+			restyled.SetRGBA(x, y, pixel)
+		}
+	}
+
+	return restyled
+}
+
+// byLuminance returns the indices of the given colors, ordered from darkest to
+// lightest.
+func byLuminance(colors []color.RGBA) []int {
+	indices := make([]int, len(colors))
+	for index := range indices {
+		indices[index] = index
+	}
+
+	sort.SliceStable(indices, func(i int, j int) bool {
+		return Luminance(colors[indices[i]]) < Luminance(colors[indices[j]])
+	})
+
+	return indices
+}
+
+// offset moves the given pixel by the difference between the given colors,
+// clamping every component to within [0, 255].
+func offset(pixel color.RGBA, from color.RGBA, to color.RGBA) color.RGBA {
+	shift := func(component uint8, from uint8, to uint8) uint8 {
+		value := int(component) - int(from) + int(to)
+		switch {
+		case value < 0:
+			return 0
+		case value > 255:
+			return 255
+		default:
+			return uint8(value)
+		}
+	}
+
+	return color.RGBA{
+		shift(pixel.R, from.R, to.R),
+		shift(pixel.G, from.G, to.G),
+		shift(pixel.B, from.B, to.B),
+		0xFF,
+	}
+}
+
+// transferLevels is the number of levels each image is quantized to by
+// Transfer, yielding four clusters of pixels.
+const transferLevels = 2
+
+// labStats accumulates the mean & standard deviation of the components of a
+// set of CIE L*a*b* colors.
+type labStats struct {
+	count   float64
+	sums    [3]float64
+	squares [3]float64
+}
+
+// add includes the given color in the statistics.
+func (s *labStats) add(lab Lab) {
+	s.count++
+	for channel, value := range [3]float64{lab.L, lab.A, lab.B} {
+		s.sums[channel] += value
+		s.squares[channel] += value * value
+	}
+}
+
+// mean returns the mean of the given component.
+func (s labStats) mean(channel int) float64 {
+	return s.sums[channel] / s.count
+}
+
+// deviation returns the standard deviation of the given component.
+func (s labStats) deviation(channel int) float64 {
+	return deviation(s.squares[channel], s.mean(channel), s.count)
+}
+
+// Transfer takes in a source and a reference image, and returns a copy of the
+// source image recolored to match the reference, using Reinhard color transfer
+// in CIE L*a*b*. Rather than matching the statistics of whole images, both are
+// quantized into clusters, which are matched by rank of luminance, and every
+// source pixel is transformed by the statistics of its own cluster and of the
+// matching reference cluster. This keeps distinct regions, such as a sky and
+// the ground, from being smeared into a single color cast. An empty reference
+// image leaves the source image unchanged.
+func Transfer(src image.Image, ref image.Image) *image.RGBA {
+	rect := src.Bounds()
+	transferred := image.NewRGBA(rect)
+
+	sources := Image(src, transferLevels)
+	references := Image(ref, transferLevels)
+
+	// Match every source cluster with a reference cluster
+	matched := make([]int, len(sources))
+	ranks := byLuminance(references)
+	for rank, index := range byLuminance(sources) {
+		matched[index] = ranks[rank]
+	}
+
+	// Gather the statistics of every reference cluster, and of the whole
+	// reference image for clusters that end up empty
+	referenceStats := make([]labStats, len(references))
+	var total labStats
+
+	refRect := ref.Bounds()
+	for x := refRect.Min.X; x < refRect.Max.X; x++ {
+		for y := refRect.Min.Y; y < refRect.Max.Y; y++ {
+			pixel := rgbaAt(ref, x, y)
+			lab := ToLab(pixel)
+
+			index, _ := nearest(references, pixel)
+			referenceStats[index].add(lab)
+			total.add(lab)
+		}
+	}
+
+	// Gather the statistics of every source cluster, keeping the cluster and
+	// color of every pixel for the second pass
+	sourceStats := make([]labStats, len(sources))
+	clusters := make([]int, 0, rect.Dx()*rect.Dy())
+	labs := make([]Lab, 0, rect.Dx()*rect.Dy())
+
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			pixel := rgbaAt(src, x, y)
+			lab := ToLab(pixel)
+
+			index, _ := nearest(sources, pixel)
+			sourceStats[index].add(lab)
+			clusters = append(clusters, index)
+			labs = append(labs, lab)
+		}
+	}
+
+	height := rect.Dy()
+	for index, lab := range labs {
+		x, y := rect.Min.X+index/height, rect.Min.Y+index%height
+
+		if total.count == 0 {
+			transferred.SetRGBA(x, y, rgbaAt(src, x, y))
+			continue
+		}
+
+		from := sourceStats[clusters[index]]
+		to := referenceStats[matched[clusters[index]]]
+		if to.count == 0 {
+			to = total
+		}
+
+		values := [3]float64{lab.L, lab.A, lab.B}
+		for channel, value := range values {
+
+			// Uniform clusters are shifted, rather than scaled
+			scale := 1.0
+			if spread := from.deviation(channel); spread > 0 {
+				scale = to.deviation(channel) / spread
+			}
+
+			values[channel] = (value-from.mean(channel))*scale + to.mean(channel)
+		}
+
+		transferred.SetRGBA(x, y, FromLab(Lab{values[0], values[1], values[2]}))
+	}
+
+	return transferred
+}
+
+// rgbaAt returns the color of the given pixel as an opaque RGB color.
+func rgbaAt(img image.Image, x int, y int) color.RGBA {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xFF}
+}
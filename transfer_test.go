@@ -0,0 +1,165 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package quantize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pixelsOf returns the pixels of the given image in row-major order.
+func pixelsOf(img *image.RGBA) []color.RGBA {
+	var pixels []color.RGBA
+
+	rect := img.Bounds()
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			pixels = append(pixels, img.RGBAAt(x, y))
+		}
+	}
+
+	return pixels
+}
+
+func TestTransferPalette(t *testing.T) {
+
+	// Dark and light gray, with a little variation within each
+	img := testImage(4, 1, func(x int, _ int) color.RGBA {
+		return []color.RGBA{
+			{40, 40, 40, 0xFF},
+			{50, 50, 50, 0xFF},
+			{200, 200, 200, 0xFF},
+			{210, 210, 210, 0xFF},
+		}[x]
+	})
+
+	tests := []struct {
+		title  string
+		target []color.RGBA
+		pixels []color.RGBA
+	}{
+		{
+			title:  "no target colors",
+			target: []color.RGBA{},
+			pixels: []color.RGBA{
+				{40, 40, 40, 0xFF},
+				{50, 50, 50, 0xFF},
+				{200, 200, 200, 0xFF},
+				{210, 210, 210, 0xFF},
+			},
+		},
+		{
+			title: "matched by luminance",
+			target: []color.RGBA{
+				{255, 240, 100, 0xFF},
+				{0, 0, 120, 0xFF},
+			},
+			pixels: []color.RGBA{
+				{0, 0, 115, 0xFF},
+				{5, 5, 125, 0xFF},
+				{250, 235, 95, 0xFF},
+				{255, 245, 105, 0xFF},
+			},
+		},
+		{
+			title: "single target color",
+			target: []color.RGBA{
+				{125, 0, 0, 0xFF},
+			},
+			pixels: []color.RGBA{
+				{40, 0, 0, 0xFF},
+				{50, 0, 0, 0xFF},
+				{200, 75, 75, 0xFF},
+				{210, 85, 85, 0xFF},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.pixels, pixelsOf(TransferPalette(img, test.target)))
+
+		})
+	}
+
+}
+
+func TestTransfer(t *testing.T) {
+
+	// The left half of each image is one color, and the right half another
+	halves := func(left color.RGBA, right color.RGBA) *image.RGBA {
+		return testImage(4, 2, func(x int, _ int) color.RGBA {
+			if x < 2 {
+				return left
+			}
+			return right
+		})
+	}
+
+	black := color.RGBA{0, 0, 0, 0xFF}
+	navy := color.RGBA{20, 30, 90, 0xFF}
+	yellow := color.RGBA{250, 220, 80, 0xFF}
+
+	tests := []struct {
+		title  string
+		src    image.Image
+		ref    image.Image
+		pixels []color.RGBA
+	}{
+		{
+			title:  "empty reference",
+			src:    halves(black, white),
+			ref:    image.NewRGBA(image.Rect(0, 0, 0, 0)),
+			pixels: []color.RGBA{black, black, white, white, black, black, white, white},
+		},
+		{
+			title:  "uniform images",
+			src:    halves(white, white),
+			ref:    halves(red, red),
+			pixels: []color.RGBA{red, red, red, red, red, red, red, red},
+		},
+		{
+			title:  "clusters matched by luminance",
+			src:    halves(black, white),
+			ref:    halves(yellow, navy),
+			pixels: []color.RGBA{navy, navy, yellow, yellow, navy, navy, yellow, yellow},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.pixels, pixelsOf(Transfer(test.src, test.ref)))
+
+		})
+	}
+
+}
+
+func TestTransferIdentity(t *testing.T) {
+
+	img := testImage(16, 16, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 16), uint8(y * 16), uint8(x * y), 0xFF}
+	})
+
+	// Transferring an image onto itself leaves it unchanged
+	for index, pixel := range pixelsOf(Transfer(img, img)) {
+		expected := img.RGBAAt(index%16, index/16)
+
+		assert.InDelta(t, expected.R, pixel.R, 1)
+		assert.InDelta(t, expected.G, pixel.G, 1)
+		assert.InDelta(t, expected.B, pixel.B, 1)
+	}
+
+}
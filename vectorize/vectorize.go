@@ -0,0 +1,340 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package vectorize traces the regions of an image remapped to a palette into
+// filled vector paths, turning photos into flat-color illustrations with one
+// layer per palette color.
+package vectorize
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/joshdk/quantize"
+)
+
+// Layer holds the outlines of every region of a single palette color. Holes
+// are outlines too, so layers are filled using the even-odd rule.
+type Layer struct {
+	Color color.RGBA
+	Paths [][]image.Point
+
+	// Pixels is the number of pixels remapped to the color.
+	Pixels int
+}
+
+// Trace takes in an image and a palette, remaps every pixel to the nearest
+// palette color, and returns a layer for every color that any pixel was
+// remapped to, in order of decreasing population. Outlines follow the edges
+// of pixels, and are then simplified so that no pixel corner strays further
+// than the given tolerance from them.
+func Trace(img image.Image, colors []color.RGBA, tolerance float64) []Layer {
+	rect := img.Bounds()
+	width, height := rect.Dx(), rect.Dy()
+
+	if len(colors) == 0 || width <= 0 || height <= 0 {
+		return []Layer{}
+	}
+
+	// Remap every pixel, in row-major order
+	indices := make([]int, width*height)
+	counts := make([]int, len(colors))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(rect.Min.X+x, rect.Min.Y+y).RGBA()
+			index := nearest(colors, color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xFF})
+
+			indices[y*width+x] = index
+			counts[index]++
+		}
+	}
+
+	layers := []Layer{}
+	for index, clr := range colors {
+		if counts[index] == 0 {
+			continue
+		}
+
+		mask := make([]bool, len(indices))
+		for pixel, assigned := range indices {
+			mask[pixel] = assigned == index
+		}
+
+		var paths [][]image.Point
+		for _, outline := range outlines(mask, width, height) {
+			paths = append(paths, simplify(outline, tolerance))
+		}
+
+		layers = append(layers, Layer{clr, paths, counts[index]})
+	}
+
+	sort.SliceStable(layers, func(i int, j int) bool {
+		return layers[i].Pixels > layers[j].Pixels
+	})
+
+	return layers
+}
+
+// EncodeSVG writes the given layers, as returned by Trace, to the given writer
+// as an SVG document of the given size. The most populous layer is drawn as
+// the background, so that no gaps are left between simplified outlines.
+func EncodeSVG(w io.Writer, layers []Layer, width int, height int) error {
+
+	if _, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height); err != nil {
+		return err
+	}
+
+	for index, layer := range layers {
+		var err error
+
+		if index == 0 {
+			_, err = fmt.Fprintf(w, "  <rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, quantize.Hex(layer.Color))
+		} else {
+			_, err = fmt.Fprintf(w, "  <path fill=\"%s\" fill-rule=\"evenodd\" d=\"%s\"/>\n", quantize.Hex(layer.Color), pathData(layer.Paths))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// pathData returns the SVG path data drawing the given closed outlines.
+func pathData(paths [][]image.Point) string {
+	var data []byte
+
+	for _, path := range paths {
+		for index, point := range path {
+			command := 'L'
+			if index == 0 {
+				command = 'M'
+			}
+			data = append(data, fmt.Sprintf("%c%d %d", command, point.X, point.Y)...)
+		}
+		data = append(data, 'Z')
+	}
+
+	return string(data)
+}
+
+// nearest returns the index of the palette color closest to the given pixel.
+func nearest(colors []color.RGBA, pixel color.RGBA) int {
+	best, bestDistance := 0, -1
+
+	for index, clr := range colors {
+		r := int(clr.R) - int(pixel.R)
+		g := int(clr.G) - int(pixel.G)
+		b := int(clr.B) - int(pixel.B)
+
+		if distance := r*r + g*g + b*b; bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = index, distance
+		}
+	}
+
+	return best
+}
+
+// edge is a single side of a pixel, directed so that the pixel is on its
+// right, and so that every outline runs clockwise around its region.
+type edge struct {
+	from image.Point
+	to   image.Point
+	used bool
+}
+
+// outlines returns the closed outlines of every region of the given mask of
+// the given size, as the corners of pixels where the outline turns.
+func outlines(mask []bool, width int, height int) [][]image.Point {
+
+	filled := func(x int, y int) bool {
+		return x >= 0 && y >= 0 && x < width && y < height && mask[y*width+x]
+	}
+
+	// Collect every pixel side that borders an unfilled pixel
+	var edges []edge
+	outgoing := make(map[image.Point][]int)
+
+	add := func(from image.Point, to image.Point) {
+		outgoing[from] = append(outgoing[from], len(edges))
+		edges = append(edges, edge{from: from, to: to})
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !filled(x, y) {
+				continue
+			}
+			if !filled(x, y-1) {
+				add(image.Pt(x, y), image.Pt(x+1, y))
+			}
+			if !filled(x+1, y) {
+				add(image.Pt(x+1, y), image.Pt(x+1, y+1))
+			}
+			if !filled(x, y+1) {
+				add(image.Pt(x+1, y+1), image.Pt(x, y+1))
+			}
+			if !filled(x-1, y) {
+				add(image.Pt(x, y+1), image.Pt(x, y))
+			}
+		}
+	}
+
+	var loops [][]image.Point
+
+	for start := range edges {
+		if edges[start].used {
+			continue
+		}
+
+		loop := []image.Point{edges[start].from}
+		current := start
+
+		for {
+			edges[current].used = true
+			vertex := edges[current].to
+			if vertex == edges[start].from {
+				break
+			}
+
+			loop = append(loop, vertex)
+			current = follow(edges, outgoing[vertex], edges[current])
+		}
+
+		loops = append(loops, corners(loop))
+	}
+
+	return loops
+}
+
+// follow returns the unused edge among the given candidates that continues
+// the given edge, preferring to turn right, so that regions touching only at
+// a corner are traced separately.
+func follow(edges []edge, candidates []int, previous edge) int {
+	direction := previous.to.Sub(previous.from)
+
+	preferences := []image.Point{
+		{-direction.Y, direction.X},
+		direction,
+		{direction.Y, -direction.X},
+	}
+
+	for _, preference := range preferences {
+		for _, candidate := range candidates {
+			if !edges[candidate].used && edges[candidate].to.Sub(edges[candidate].from) == preference {
+				return candidate
+			}
+		}
+	}
+
+	// Every vertex has as many outgoing edges as incoming ones, so an unused
+	// candidate always remains
+	for _, candidate := range candidates {
+		if !edges[candidate].used {
+			return candidate
+		}
+	}
+
+	return candidates[0]
+}
+
+// corners removes every point of the given closed outline that lies on a
+// straight line between its neighbors.
+func corners(loop []image.Point) []image.Point {
+	var kept []image.Point
+
+	for index, point := range loop {
+		previous := loop[(index+len(loop)-1)%len(loop)]
+		next := loop[(index+1)%len(loop)]
+
+		if point.Sub(previous) != next.Sub(point) {
+			kept = append(kept, point)
+		}
+	}
+
+	return kept
+}
+
+// simplify reduces the given closed outline with the Ramer-Douglas-Peucker
+// algorithm, keeping every point further than the given tolerance from the
+// simplified outline. Outlines are never reduced to fewer than three points.
+func simplify(loop []image.Point, tolerance float64) []image.Point {
+	if tolerance <= 0 || len(loop) <= 3 {
+		return loop
+	}
+
+	// Split the outline at the point furthest from the first point, and
+	// simplify both halves
+	far := 0
+	for index, point := range loop {
+		if distance(point, loop[0]) > distance(loop[far], loop[0]) {
+			far = index
+		}
+	}
+
+	closed := append(append([]image.Point{}, loop...), loop[0])
+	first := reduce(closed[:far+1], tolerance)
+	second := reduce(closed[far:], tolerance)
+
+	simplified := append(first[:len(first)-1], second[:len(second)-1]...)
+	if len(simplified) < 3 {
+		return loop
+	}
+
+	return simplified
+}
+
+// reduce simplifies the given open polyline, always keeping its endpoints.
+func reduce(line []image.Point, tolerance float64) []image.Point {
+	if len(line) < 3 {
+		return line
+	}
+
+	first, last := line[0], line[len(line)-1]
+
+	furthest, furthestDistance := 0, -1.0
+	for index := 1; index < len(line)-1; index++ {
+		if d := segmentDistance(line[index], first, last); d > furthestDistance {
+			furthest, furthestDistance = index, d
+		}
+	}
+
+	if furthestDistance <= tolerance {
+		return []image.Point{first, last}
+	}
+
+	left := reduce(line[:furthest+1], tolerance)
+	right := reduce(line[furthest:], tolerance)
+
+	return append(left[:len(left)-1], right...)
+}
+
+// distance returns the euclidean distance between two points.
+func distance(first image.Point, second image.Point) float64 {
+	return math.Hypot(float64(first.X-second.X), float64(first.Y-second.Y))
+}
+
+// segmentDistance returns the distance from the given point to the line segment
+// between the given endpoints.
+func segmentDistance(point image.Point, start image.Point, end image.Point) float64 {
+	dx, dy := float64(end.X-start.X), float64(end.Y-start.Y)
+
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return distance(point, start)
+	}
+
+	// Project the point onto the segment, clamped to its endpoints
+	t := (float64(point.X-start.X)*dx + float64(point.Y-start.Y)*dy) / lengthSquared
+	t = math.Max(0, math.Min(1, t))
+
+	return math.Hypot(float64(point.X)-(float64(start.X)+t*dx), float64(point.Y)-(float64(start.Y)+t*dy))
+}
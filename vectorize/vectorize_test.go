@@ -0,0 +1,193 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package vectorize
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseMask builds a mask from rows of text, where "#" is a filled pixel.
+func parseMask(rows ...string) ([]bool, int, int) {
+	width, height := len(rows[0]), len(rows)
+	mask := make([]bool, width*height)
+
+	for y, row := range rows {
+		for x, char := range row {
+			mask[y*width+x] = char == '#'
+		}
+	}
+
+	return mask, width, height
+}
+
+func TestOutlines(t *testing.T) {
+
+	tests := []struct {
+		title    string
+		rows     []string
+		outlines [][]image.Point
+	}{
+		{
+			title: "single pixel",
+			rows:  []string{"#"},
+			outlines: [][]image.Point{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			},
+		},
+		{
+			title: "rectangle",
+			rows: []string{
+				"....",
+				".##.",
+				".##.",
+			},
+			outlines: [][]image.Point{
+				{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+			},
+		},
+		{
+			title: "corner",
+			rows: []string{
+				"#.",
+				"##",
+			},
+			outlines: [][]image.Point{
+				{{0, 0}, {1, 0}, {1, 1}, {2, 1}, {2, 2}, {0, 2}},
+			},
+		},
+		{
+			title: "ring with a hole",
+			rows: []string{
+				"###",
+				"#.#",
+				"###",
+			},
+			outlines: [][]image.Point{
+				{{0, 0}, {3, 0}, {3, 3}, {0, 3}},
+				{{2, 1}, {1, 1}, {1, 2}, {2, 2}},
+			},
+		},
+		{
+			title: "touching only at a corner",
+			rows: []string{
+				"#.",
+				".#",
+			},
+			outlines: [][]image.Point{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+				{{1, 1}, {2, 1}, {2, 2}, {1, 2}},
+			},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			mask, width, height := parseMask(test.rows...)
+			assert.Equal(t, test.outlines, outlines(mask, width, height))
+
+		})
+	}
+
+}
+
+func TestSimplify(t *testing.T) {
+
+	// A staircase outline of a triangle
+	stairs := []image.Point{
+		{0, 0}, {1, 0}, {1, 1}, {2, 1}, {2, 2}, {3, 2}, {3, 3}, {0, 3},
+	}
+
+	tests := []struct {
+		title     string
+		tolerance float64
+		outline   []image.Point
+	}{
+		{
+			title:     "no tolerance",
+			tolerance: 0,
+			outline:   stairs,
+		},
+		{
+			title:     "small tolerance",
+			tolerance: 0.5,
+			outline:   []image.Point{{0, 0}, {1, 0}, {1, 1}, {3, 2}, {3, 3}, {0, 3}},
+		},
+		{
+			title:     "large tolerance",
+			tolerance: 1,
+			outline:   []image.Point{{0, 0}, {3, 3}, {0, 3}},
+		},
+	}
+
+	for index, test := range tests {
+		name := fmt.Sprintf("Case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.outline, simplify(stairs, test.tolerance))
+
+		})
+	}
+
+}
+
+func TestTrace(t *testing.T) {
+
+	red := color.RGBA{255, 0, 0, 0xFF}
+	blue := color.RGBA{0, 0, 255, 0xFF}
+	green := color.RGBA{0, 255, 0, 0xFF}
+
+	// A red square in the middle of a blue image
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			if x >= 1 && x < 3 && y >= 1 && y < 3 {
+				img.SetRGBA(x, y, color.RGBA{250, 10, 0, 0xFF})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{0, 10, 240, 0xFF})
+			}
+		}
+	}
+
+	layers := Trace(img, []color.RGBA{red, green, blue}, 0)
+
+	require.Equal(t, 2, len(layers))
+
+	assert.Equal(t, blue, layers[0].Color)
+	assert.Equal(t, 12, layers[0].Pixels)
+	assert.Equal(t, [][]image.Point{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		{{1, 1}, {1, 3}, {3, 3}, {3, 1}},
+	}, layers[0].Paths)
+
+	assert.Equal(t, red, layers[1].Color)
+	assert.Equal(t, 4, layers[1].Pixels)
+	assert.Equal(t, [][]image.Point{
+		{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+	}, layers[1].Paths)
+
+	var buf bytes.Buffer
+	require.Nil(t, EncodeSVG(&buf, layers, 4, 4))
+
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="4" height="4" viewBox="0 0 4 4">
+  <rect width="4" height="4" fill="#0000FF"/>
+  <path fill="#FF0000" fill-rule="evenodd" d="M1 1L3 1L3 3L1 3Z"/>
+</svg>
+`, buf.String())
+
+	assert.Equal(t, []Layer{}, Trace(img, []color.RGBA{}, 0))
+	assert.Equal(t, []Layer{}, Trace(image.NewRGBA(image.Rect(0, 0, 0, 0)), []color.RGBA{red}, 0))
+
+}
@@ -0,0 +1,36 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+//go:build vips
+// +build vips
+
+package quantize
+
+import (
+	"image/color"
+	"unsafe"
+)
+
+// maxPointerBuffer is the largest raw pixel buffer that RGBPointer accepts.
+const maxPointerBuffer = 1 << 30
+
+// RGBPointer performs MMCQ like RGBBuffer, but takes the address of raw pixels
+// held outside of the Go heap, such as the memory returned by
+// vips_image_write_to_memory through govips or bimg, so that they are never
+// copied into a Go slice first. The memory must remain valid until RGBPointer
+// returns. Only built with the vips build tag.
+func RGBPointer(data unsafe.Pointer, width int, height int, stride int, bands int, levels int) ([]color.RGBA, error) {
+
+	if data == nil || width <= 0 || height <= 0 || stride < width*bands {
+		return nil, ErrInvalidBuffer
+	}
+
+	size := stride*(height-1) + width*bands
+	if size > maxPointerBuffer {
+		return nil, ErrInvalidBuffer
+	}
+
+	buf := (*[maxPointerBuffer]byte)(data)[:size:size]
+	return RGBBuffer(buf, width, height, stride, bands, levels)
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+//go:build vips
+// +build vips
+
+package quantize
+
+import (
+	"image/color"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRGBPointer(t *testing.T) {
+
+	img := testImage(5, 3, func(x int, y int) color.RGBA {
+		return color.RGBA{uint8(x * 40), uint8(y * 80), 0x20, 0xFF}
+	})
+	buf, stride := packBuffer(img, 4, 4)
+
+	colors, err := RGBPointer(unsafe.Pointer(&buf[0]), 5, 3, stride, 4, 2)
+	require.Nil(t, err)
+	assert.Equal(t, Image(img, 2), colors)
+
+	_, err = RGBPointer(nil, 5, 3, stride, 4, 2)
+	assert.Equal(t, ErrInvalidBuffer, err)
+}